@@ -2,12 +2,9 @@ package client
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
-
-	"github.com/lubluniky/clob-client-go/internal/transport"
 )
 
 // GetBalanceAllowance returns the balance and allowance for a given asset.
@@ -25,25 +22,15 @@ func (c *ClobClient) GetBalanceAllowance(ctx context.Context, params BalanceAllo
 		query["signature_type"] = strconv.Itoa(int(sigType))
 	}
 
-	headers, err := c.l2Headers("GET", EndpointBalanceAllowance, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointBalanceAllowance, headers, query)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
+	result, err := call[BalanceAllowance](ctx, c, apiRequest{
+		method: http.MethodGet,
+		path:   EndpointBalanceAllowance,
+		query:  query,
+		auth:   authL2,
+	}, "balance allowance")
 	if err != nil {
 		return nil, err
 	}
-
-	var result BalanceAllowance
-	if err := json.Unmarshal(raw, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing balance allowance: %w", err)
-	}
 	return &result, nil
 }
 
@@ -55,47 +42,22 @@ func (c *ClobClient) GetNotifications(ctx context.Context, signatureType Signatu
 		"signature_type": strconv.Itoa(int(signatureType)),
 	}
 
-	headers, err := c.l2Headers("GET", EndpointNotifications, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointNotifications, headers, query)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	var notifications []Notification
-	if err := json.Unmarshal(raw, &notifications); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing notifications: %w", err)
-	}
-	return notifications, nil
+	return call[[]Notification](ctx, c, apiRequest{
+		method: http.MethodGet,
+		path:   EndpointNotifications,
+		query:  query,
+		auth:   authL2,
+	}, "notifications")
 }
 
 // DropNotifications deletes notifications by their IDs.
 // Requires L2 authentication.
 func (c *ClobClient) DropNotifications(ctx context.Context, ids []string) error {
-	headers, err := c.l2Headers("DELETE", EndpointNotifications, "")
-	if err != nil {
-		return err
-	}
-
-	path := EndpointNotifications
+	req := apiRequest{method: http.MethodDelete, path: EndpointNotifications, auth: authL2}
 	if len(ids) > 0 {
-		path += "?ids=" + strings.Join(ids, ",")
-	}
-
-	resp, err := c.http.Delete(ctx, path, headers, nil)
-	if err != nil {
-		return err
+		req.rawQuery = "ids=" + strings.Join(ids, ",")
 	}
-
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, req)
 	return err
 }
 
@@ -114,17 +76,12 @@ func (c *ClobClient) UpdateBalanceAllowance(ctx context.Context, params BalanceA
 		query["signature_type"] = strconv.Itoa(int(sigType))
 	}
 
-	headers, err := c.l2Headers("GET", EndpointUpdateBalanceAllowance, "")
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointUpdateBalanceAllowance, headers, query)
-	if err != nil {
-		return err
-	}
-
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, apiRequest{
+		method: http.MethodGet,
+		path:   EndpointUpdateBalanceAllowance,
+		query:  query,
+		auth:   authL2,
+	})
 	return err
 }
 
@@ -136,21 +93,6 @@ func (c *ClobClient) PostHeartbeat(ctx context.Context, heartbeatID string) erro
 		reqBody.HeartbeatID = nil
 	}
 
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("polymarket: marshalling heartbeat request: %w", err)
-	}
-
-	headers, err := c.l2Headers("POST", EndpointHeartbeat, string(bodyBytes))
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.http.Post(ctx, EndpointHeartbeat, headers, reqBody)
-	if err != nil {
-		return err
-	}
-
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, apiRequest{method: http.MethodPost, path: EndpointHeartbeat, body: reqBody, auth: authL2})
 	return err
 }
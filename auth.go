@@ -4,33 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-
-	"github.com/lubluniky/clob-client-go/internal/transport"
+	"net/http"
 )
 
 // CreateApiKey creates a new API key using L1 (EIP-712 wallet) authentication.
 // The nonce should be a unique value for each request (0 is commonly used for
 // the first call).
 func (c *ClobClient) CreateApiKey(ctx context.Context, nonce int) (*ApiCreds, error) {
-	headers, err := c.l1Headers(nonce)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Post(ctx, EndpointCreateApiKey, headers, nil)
+	creds, err := call[ApiCreds](ctx, c, apiRequest{
+		method: http.MethodPost,
+		path:   EndpointCreateApiKey,
+		auth:   authL1,
+		nonce:  nonce,
+	}, "api key response")
 	if err != nil {
 		return nil, err
 	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	var creds ApiCreds
-	if err := json.Unmarshal(raw, &creds); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing api key response: %w", err)
-	}
 	return &creds, nil
 }
 
@@ -38,25 +27,15 @@ func (c *ClobClient) CreateApiKey(ctx context.Context, nonce int) (*ApiCreds, er
 // authentication. If a key was previously created for this wallet, this will
 // return the same credentials.
 func (c *ClobClient) DeriveApiKey(ctx context.Context, nonce int) (*ApiCreds, error) {
-	headers, err := c.l1Headers(nonce)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointDeriveApiKey, headers, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
+	creds, err := call[ApiCreds](ctx, c, apiRequest{
+		method: http.MethodGet,
+		path:   EndpointDeriveApiKey,
+		auth:   authL1,
+		nonce:  nonce,
+	}, "derived api key response")
 	if err != nil {
 		return nil, err
 	}
-
-	var creds ApiCreds
-	if err := json.Unmarshal(raw, &creds); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing derived api key response: %w", err)
-	}
 	return &creds, nil
 }
 
@@ -77,17 +56,7 @@ func (c *ClobClient) CreateOrDeriveApiKey(ctx context.Context) (*ApiCreds, error
 // GetApiKeys returns all API keys for the authenticated user.
 // Requires L2 authentication.
 func (c *ClobClient) GetApiKeys(ctx context.Context) ([]ApiKeyResponse, error) {
-	headers, err := c.l2Headers("GET", EndpointGetApiKeys, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointGetApiKeys, headers, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
+	raw, err := c.send(ctx, apiRequest{method: http.MethodGet, path: EndpointGetApiKeys, auth: authL2})
 	if err != nil {
 		return nil, err
 	}
@@ -116,64 +85,31 @@ func (c *ClobClient) GetApiKeys(ctx context.Context) ([]ApiKeyResponse, error) {
 // GetClosedOnlyMode returns whether this account is in closed-only mode.
 // Requires L2 authentication.
 func (c *ClobClient) GetClosedOnlyMode(ctx context.Context) (*BanStatus, error) {
-	headers, err := c.l2Headers("GET", EndpointClosedOnly, "")
+	result, err := call[BanStatus](ctx, c, apiRequest{method: http.MethodGet, path: EndpointClosedOnly, auth: authL2}, "closed-only mode")
 	if err != nil {
 		return nil, err
 	}
-
-	resp, err := c.http.Get(ctx, EndpointClosedOnly, headers, nil)
-	if err != nil {
-		return nil, err
-	}
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	var result BanStatus
-	if err := json.Unmarshal(raw, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing closed-only mode: %w", err)
-	}
 	return &result, nil
 }
 
 // CreateReadonlyApiKey creates a new readonly API key.
 // Requires L2 authentication.
 func (c *ClobClient) CreateReadonlyApiKey(ctx context.Context) (*ReadonlyApiKeyResponse, error) {
-	headers, err := c.l2Headers("POST", EndpointCreateReadonlyApiKey, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Post(ctx, EndpointCreateReadonlyApiKey, headers, nil)
+	result, err := call[ReadonlyApiKeyResponse](ctx, c, apiRequest{
+		method: http.MethodPost,
+		path:   EndpointCreateReadonlyApiKey,
+		auth:   authL2,
+	}, "readonly api key")
 	if err != nil {
 		return nil, err
 	}
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	var result ReadonlyApiKeyResponse
-	if err := json.Unmarshal(raw, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing readonly api key: %w", err)
-	}
 	return &result, nil
 }
 
 // GetReadonlyApiKeys returns all readonly API keys for this account.
 // Requires L2 authentication.
 func (c *ClobClient) GetReadonlyApiKeys(ctx context.Context) ([]string, error) {
-	headers, err := c.l2Headers("GET", EndpointGetReadonlyApiKeys, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointGetReadonlyApiKeys, headers, nil)
-	if err != nil {
-		return nil, err
-	}
-	raw, err := transport.ParseResponse(resp)
+	raw, err := c.send(ctx, apiRequest{method: http.MethodGet, path: EndpointGetReadonlyApiKeys, auth: authL2})
 	if err != nil {
 		return nil, err
 	}
@@ -195,29 +131,19 @@ func (c *ClobClient) GetReadonlyApiKeys(ctx context.Context) ([]string, error) {
 // DeleteReadonlyApiKey deletes one readonly API key.
 // Requires L2 authentication.
 func (c *ClobClient) DeleteReadonlyApiKey(ctx context.Context, key string) error {
-	reqBody := map[string]string{"key": key}
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("polymarket: marshalling delete readonly api key request: %w", err)
-	}
-
-	headers, err := c.l2Headers("DELETE", EndpointDeleteReadonlyApiKey, string(bodyBytes))
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.http.Delete(ctx, EndpointDeleteReadonlyApiKey, headers, reqBody)
-	if err != nil {
-		return err
-	}
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, apiRequest{
+		method: http.MethodDelete,
+		path:   EndpointDeleteReadonlyApiKey,
+		body:   map[string]string{"key": key},
+		auth:   authL2,
+	})
 	return err
 }
 
 // ValidateReadonlyApiKey validates a readonly key for an address.
 // This endpoint is public (L0).
 func (c *ClobClient) ValidateReadonlyApiKey(ctx context.Context, address, key string) (string, error) {
-	resp, err := c.http.Get(ctx, EndpointValidateReadonlyApiKey, c.l0Headers(), map[string]string{
+	raw, err := c.getJSON(ctx, EndpointValidateReadonlyApiKey, map[string]string{
 		"address": address,
 		"key":     key,
 	})
@@ -225,11 +151,6 @@ func (c *ClobClient) ValidateReadonlyApiKey(ctx context.Context, address, key st
 		return "", err
 	}
 
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return "", err
-	}
-
 	var result string
 	if err := json.Unmarshal(raw, &result); err != nil {
 		// Fallback to plain text body.
@@ -240,16 +161,6 @@ func (c *ClobClient) ValidateReadonlyApiKey(ctx context.Context, address, key st
 
 // DeleteApiKey deletes the current API key. Requires L2 authentication.
 func (c *ClobClient) DeleteApiKey(ctx context.Context) error {
-	headers, err := c.l2Headers("DELETE", EndpointDeleteApiKey, "")
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.http.Delete(ctx, EndpointDeleteApiKey, headers, nil)
-	if err != nil {
-		return err
-	}
-
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, apiRequest{method: http.MethodDelete, path: EndpointDeleteApiKey, auth: authL2})
 	return err
 }
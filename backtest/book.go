@@ -0,0 +1,132 @@
+package backtest
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+// Level is one price level of a Book.
+type Level struct {
+	Price decimal.Decimal
+	Size  decimal.Decimal
+}
+
+// Book is a local L2 order book for one asset, maintained from book snapshots
+// and price_change deltas. It is not safe for concurrent use.
+type Book struct {
+	bids map[string]Level
+	asks map[string]Level
+}
+
+// NewBook returns an empty book.
+func NewBook() *Book {
+	return &Book{bids: make(map[string]Level), asks: make(map[string]Level)}
+}
+
+// ApplySnapshot replaces the book with a "book" event.
+func (b *Book) ApplySnapshot(u ws.BookUpdate) {
+	b.bids = make(map[string]Level, len(u.Bids))
+	b.asks = make(map[string]Level, len(u.Asks))
+	for _, l := range u.Bids {
+		b.set(polymarket.Buy, parseDecimal(l.Price), parseDecimal(l.Size))
+	}
+	for _, l := range u.Asks {
+		b.set(polymarket.Sell, parseDecimal(l.Price), parseDecimal(l.Size))
+	}
+}
+
+// ApplyChange applies one price_change entry. A zero size removes the level.
+func (b *Book) ApplyChange(e ws.PriceChangeEntry) {
+	b.set(polymarket.Side(e.Side), parseDecimal(e.Price), parseDecimal(e.Size))
+}
+
+// set replaces the level at price on side; BUY is the bid side.
+func (b *Book) set(side polymarket.Side, price, size decimal.Decimal) {
+	levels := b.side(side)
+	key := price.String()
+	if !size.IsPositive() {
+		delete(levels, key)
+		return
+	}
+	levels[key] = Level{Price: price, Size: size}
+}
+
+func (b *Book) side(side polymarket.Side) map[string]Level {
+	if side == polymarket.Buy {
+		return b.bids
+	}
+	return b.asks
+}
+
+// SizeAt returns the resting size at price on side.
+func (b *Book) SizeAt(side polymarket.Side, price decimal.Decimal) decimal.Decimal {
+	return b.side(side)[price.String()].Size
+}
+
+// BestBid returns the highest bid.
+func (b *Book) BestBid() (Level, bool) {
+	var best Level
+	found := false
+	for _, l := range b.bids {
+		if !found || l.Price.GreaterThan(best.Price) {
+			best, found = l, true
+		}
+	}
+	return best, found
+}
+
+// BestAsk returns the lowest ask.
+func (b *Book) BestAsk() (Level, bool) {
+	var best Level
+	found := false
+	for _, l := range b.asks {
+		if !found || l.Price.LessThan(best.Price) {
+			best, found = l, true
+		}
+	}
+	return best, found
+}
+
+// Mid returns the midpoint of the best bid and ask.
+func (b *Book) Mid() (decimal.Decimal, bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return decimal.Zero, false
+	}
+	return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), true
+}
+
+// Bids returns bid levels, best (highest) first.
+func (b *Book) Bids() []Level {
+	out := levels(b.bids)
+	sort.Slice(out, func(i, j int) bool { return out[i].Price.GreaterThan(out[j].Price) })
+	return out
+}
+
+// Asks returns ask levels, best (lowest) first.
+func (b *Book) Asks() []Level {
+	out := levels(b.asks)
+	sort.Slice(out, func(i, j int) bool { return out[i].Price.LessThan(out[j].Price) })
+	return out
+}
+
+func levels(m map[string]Level) []Level {
+	out := make([]Level, 0, len(m))
+	for _, l := range m {
+		out = append(out, l)
+	}
+	return out
+}
+
+func parseDecimal(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
@@ -0,0 +1,519 @@
+// Package backtest replays market data logs written by the recorder package
+// through local order books and a simulated matching engine, driving the
+// same strategy.Strategy used live.
+//
+// The simulation is event-driven: recorded events update the exchange-side
+// books at their receive time, while the strategy sees them, places orders and
+// learns of fills after delays drawn from a LatencyModel.
+//
+// Matching model:
+//   - An arriving order that crosses the book takes visible liquidity up to
+//     its limit price, depleting the local levels until the next update.
+//   - A resting order joins the back of the queue at its price. Trades at that
+//     price consume the queue ahead of it before filling it; each resting
+//     order sees the full size of every trade.
+//   - A resting order fills completely if a trade prints through its price or
+//     the opposite side of the book moves across it.
+package backtest
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"iter"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/recorder"
+	"github.com/lubluniky/clob-client-go/strategy"
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+// Result summarises a backtest run.
+type Result struct {
+	Start, End time.Time
+	Events     int
+
+	OrdersPlaced   int
+	OrdersCanceled int
+	// OrdersRejected counts post-only orders that would have crossed.
+	OrdersRejected int
+	// OrdersKilled counts FOK/FAK orders (or remainders) that did not fill.
+	OrdersKilled int
+
+	Fills  []strategy.Fill
+	Volume decimal.Decimal // filled notional
+	Fees   decimal.Decimal
+
+	Cash      decimal.Decimal
+	Positions map[string]decimal.Decimal // token ID -> shares
+	// Marks holds the last midpoint (or trade price when no two-sided book
+	// was seen) per token, used to value open positions.
+	Marks map[string]decimal.Decimal
+}
+
+// PnL returns cash plus open positions valued at their marks.
+func (r *Result) PnL() decimal.Decimal {
+	pnl := r.Cash
+	for token, pos := range r.Positions {
+		pnl = pnl.Add(pos.Mul(r.Marks[token]))
+	}
+	return pnl
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithLatency sets the latency model. The default is zero latency.
+func WithLatency(m LatencyModel) Option {
+	return func(e *Engine) {
+		if m != nil {
+			e.latency = m
+		}
+	}
+}
+
+// WithTakerFeeBps charges taker fills the CLOB fee formula:
+// bps/10000 * min(price, 1-price) * size. Maker fills are free.
+func WithTakerFeeBps(bps int) Option {
+	return func(e *Engine) { e.takerFeeBps = bps }
+}
+
+// Engine runs one backtest. It implements strategy.Exchange for the strategy
+// it drives and is not safe for concurrent use.
+type Engine struct {
+	strategy    strategy.Strategy
+	latency     LatencyModel
+	takerFeeBps int
+
+	now     time.Time
+	seq     uint64
+	queue   actionQueue
+	books   map[string]*Book
+	orders  map[string]*simOrder // placed and not yet done
+	resting []*simOrder          // arrival order
+	nextID  int
+
+	result Result
+}
+
+type simOrder struct {
+	strategy.Order
+	id         string
+	remaining  decimal.Decimal
+	queueAhead decimal.Decimal
+	canceled   bool
+	done       bool
+}
+
+// New returns an engine that drives s.
+func New(s strategy.Strategy, opts ...Option) *Engine {
+	e := &Engine{
+		strategy: s,
+		latency:  FixedLatency{},
+		books:    make(map[string]*Book),
+		orders:   make(map[string]*simOrder),
+		result: Result{
+			Positions: make(map[string]decimal.Decimal),
+			Marks:     make(map[string]decimal.Decimal),
+		},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Run replays events (typically recorder.Reader.Events) and returns the
+// result. Scheduled work still pending when the log ends (order arrivals,
+// fill notifications) is completed against the final book state. On a read
+// error Run returns the partial result along with the error.
+func (e *Engine) Run(events iter.Seq2[recorder.Event, error]) (*Result, error) {
+	for ev, err := range events {
+		if err != nil {
+			e.drain(e.now)
+			return e.finish(), err
+		}
+		e.drain(ev.Time)
+		e.advance(ev.Time)
+		if e.result.Start.IsZero() {
+			e.result.Start = e.now
+		}
+		e.result.Events++
+		e.apply(ev)
+		e.schedule(e.latency.Feed(), func() { e.deliver(ev) })
+	}
+	e.drain(time.Time{})
+	return e.finish(), nil
+}
+
+// Book returns the exchange-side book for tokenID, or nil if no data has been
+// seen. Strategies should maintain their own view from callbacks; this is
+// intended for inspection after or between runs.
+func (e *Engine) Book(tokenID string) *Book {
+	return e.books[tokenID]
+}
+
+func (e *Engine) finish() *Result {
+	e.result.End = e.now
+	return &e.result
+}
+
+// ---------------------------------------------------------------------------
+// strategy.Exchange
+// ---------------------------------------------------------------------------
+
+// Now returns the simulated time.
+func (e *Engine) Now() time.Time { return e.now }
+
+// PlaceOrder validates o and schedules its arrival at the matcher after the
+// order latency. The returned ID is valid immediately.
+func (e *Engine) PlaceOrder(o strategy.Order) (string, error) {
+	if o.TokenID == "" {
+		return "", errors.New("backtest: order token ID is required")
+	}
+	if o.Side != polymarket.Buy && o.Side != polymarket.Sell {
+		return "", fmt.Errorf("backtest: invalid order side %q", o.Side)
+	}
+	if !o.Price.IsPositive() || o.Price.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return "", fmt.Errorf("backtest: order price %s out of range (0, 1)", o.Price)
+	}
+	if !o.Size.IsPositive() {
+		return "", errors.New("backtest: order size must be positive")
+	}
+	if o.Type == "" {
+		o.Type = polymarket.GTC
+	}
+	if o.PostOnly && o.Type != polymarket.GTC && o.Type != polymarket.GTD {
+		return "", errors.New("backtest: postOnly is only supported for GTC and GTD orders")
+	}
+
+	e.nextID++
+	so := &simOrder{Order: o, id: "sim-" + strconv.Itoa(e.nextID), remaining: o.Size}
+	e.orders[so.id] = so
+	e.result.OrdersPlaced++
+	e.schedule(e.latency.Order(), func() { e.arrive(so) })
+	return so.id, nil
+}
+
+// CancelOrder schedules a cancel after the cancel latency. Orders that fill
+// before the cancel arrives stay filled.
+func (e *Engine) CancelOrder(orderID string) error {
+	so, ok := e.orders[orderID]
+	if !ok {
+		return fmt.Errorf("backtest: order %s is not open", orderID)
+	}
+	e.schedule(e.latency.Cancel(), func() {
+		if so.done || so.canceled {
+			return
+		}
+		so.canceled = true
+		e.result.OrdersCanceled++
+		e.retire(so)
+	})
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Exchange side
+// ---------------------------------------------------------------------------
+
+func (e *Engine) book(tokenID string) *Book {
+	b, ok := e.books[tokenID]
+	if !ok {
+		b = NewBook()
+		e.books[tokenID] = b
+	}
+	return b
+}
+
+// apply updates exchange-side state with a recorded event.
+func (e *Engine) apply(ev recorder.Event) {
+	switch {
+	case ev.Book != nil:
+		e.book(ev.Book.AssetID).ApplySnapshot(*ev.Book)
+		e.bookMoved(ev.Book.AssetID)
+	case ev.PriceChange != nil:
+		touched := make(map[string]struct{})
+		for _, c := range ev.PriceChange.PriceChanges {
+			e.book(c.AssetID).ApplyChange(c)
+			touched[c.AssetID] = struct{}{}
+		}
+		for _, id := range sortedKeys(touched) {
+			e.bookMoved(id)
+		}
+	case ev.LastTrade != nil:
+		e.trade(*ev.LastTrade)
+	}
+}
+
+// arrive runs an order through the matcher.
+func (e *Engine) arrive(so *simOrder) {
+	if so.canceled {
+		return
+	}
+	b := e.book(so.TokenID)
+
+	if so.PostOnly && e.crosses(so, b) {
+		e.result.OrdersRejected++
+		e.retire(so)
+		return
+	}
+
+	opposite := b.Asks()
+	oppSide := polymarket.Sell
+	if so.Side == polymarket.Sell {
+		opposite = b.Bids()
+		oppSide = polymarket.Buy
+	}
+
+	if so.Type == polymarket.FOK {
+		available := decimal.Zero
+		for _, l := range opposite {
+			if !e.priceOK(so, l.Price) {
+				break
+			}
+			available = available.Add(l.Size)
+		}
+		if available.LessThan(so.remaining) {
+			e.result.OrdersKilled++
+			e.retire(so)
+			return
+		}
+	}
+
+	for _, l := range opposite {
+		if so.remaining.IsZero() || !e.priceOK(so, l.Price) {
+			break
+		}
+		qty := decimal.Min(l.Size, so.remaining)
+		b.set(oppSide, l.Price, l.Size.Sub(qty))
+		e.fill(so, l.Price, qty, false)
+	}
+	if so.done {
+		return
+	}
+
+	switch so.Type {
+	case polymarket.FOK, polymarket.FAK:
+		e.result.OrdersKilled++
+		e.retire(so)
+	default:
+		so.queueAhead = b.SizeAt(so.Side, so.Price)
+		e.resting = append(e.resting, so)
+	}
+}
+
+// priceOK reports whether a resting level at price is marketable for so.
+func (e *Engine) priceOK(so *simOrder, price decimal.Decimal) bool {
+	if so.Side == polymarket.Buy {
+		return price.LessThanOrEqual(so.Price)
+	}
+	return price.GreaterThanOrEqual(so.Price)
+}
+
+func (e *Engine) crosses(so *simOrder, b *Book) bool {
+	if so.Side == polymarket.Buy {
+		ask, ok := b.BestAsk()
+		return ok && ask.Price.LessThanOrEqual(so.Price)
+	}
+	bid, ok := b.BestBid()
+	return ok && bid.Price.GreaterThanOrEqual(so.Price)
+}
+
+// bookMoved shrinks queue positions to the visible size and fills resting
+// orders the opposite side has moved across.
+func (e *Engine) bookMoved(tokenID string) {
+	b := e.books[tokenID]
+	if mid, ok := b.Mid(); ok {
+		e.result.Marks[tokenID] = mid
+	}
+	for _, so := range e.restingFor(tokenID) {
+		if own := b.SizeAt(so.Side, so.Price); own.LessThan(so.queueAhead) {
+			so.queueAhead = own
+		}
+		if e.crosses(so, b) {
+			e.fill(so, so.Price, so.remaining, true)
+		}
+	}
+}
+
+// trade applies a last_trade_price print to resting orders.
+func (e *Engine) trade(t ws.LastTradePrice) {
+	price := parseDecimal(t.Price)
+	size := parseDecimal(t.Size)
+	if _, ok := e.book(t.AssetID).Mid(); !ok {
+		e.result.Marks[t.AssetID] = price
+	}
+
+	for _, so := range e.restingFor(t.AssetID) {
+		var through, at bool
+		if so.Side == polymarket.Buy {
+			through = price.LessThan(so.Price)
+			at = price.Equal(so.Price) && t.Side != string(polymarket.Buy)
+		} else {
+			through = price.GreaterThan(so.Price)
+			at = price.Equal(so.Price) && t.Side != string(polymarket.Sell)
+		}
+		switch {
+		case through:
+			e.fill(so, so.Price, so.remaining, true)
+		case at:
+			ahead := so.queueAhead.Sub(size)
+			if ahead.IsNegative() {
+				e.fill(so, so.Price, decimal.Min(ahead.Neg(), so.remaining), true)
+				ahead = decimal.Zero
+			}
+			so.queueAhead = ahead
+		}
+	}
+}
+
+func (e *Engine) restingFor(tokenID string) []*simOrder {
+	var out []*simOrder
+	for _, so := range e.resting {
+		if so.TokenID == tokenID && !so.done {
+			out = append(out, so)
+		}
+	}
+	return out
+}
+
+// fill books an execution and schedules its delivery to the strategy.
+func (e *Engine) fill(so *simOrder, price, qty decimal.Decimal, maker bool) {
+	if !qty.IsPositive() {
+		return
+	}
+	so.remaining = so.remaining.Sub(qty)
+
+	notional := price.Mul(qty)
+	r := &e.result
+	r.Volume = r.Volume.Add(notional)
+	if so.Side == polymarket.Buy {
+		r.Cash = r.Cash.Sub(notional)
+		r.Positions[so.TokenID] = r.Positions[so.TokenID].Add(qty)
+	} else {
+		r.Cash = r.Cash.Add(notional)
+		r.Positions[so.TokenID] = r.Positions[so.TokenID].Sub(qty)
+	}
+	if !maker && e.takerFeeBps > 0 {
+		fee := decimal.New(int64(e.takerFeeBps), -4).
+			Mul(decimal.Min(price, decimal.NewFromInt(1).Sub(price))).
+			Mul(qty)
+		r.Fees = r.Fees.Add(fee)
+		r.Cash = r.Cash.Sub(fee)
+	}
+
+	f := strategy.Fill{
+		OrderID: so.id,
+		TokenID: so.TokenID,
+		Side:    so.Side,
+		Price:   price,
+		Size:    qty,
+		Maker:   maker,
+		Time:    e.now,
+	}
+	r.Fills = append(r.Fills, f)
+	e.schedule(e.latency.Feed(), func() { e.strategy.OnFill(e, f) })
+
+	if !so.remaining.IsPositive() {
+		e.retire(so)
+	}
+}
+
+// retire removes a finished order from the open and resting sets.
+func (e *Engine) retire(so *simOrder) {
+	so.done = true
+	delete(e.orders, so.id)
+	kept := e.resting[:0]
+	for _, r := range e.resting {
+		if !r.done {
+			kept = append(kept, r)
+		}
+	}
+	e.resting = kept
+}
+
+// ---------------------------------------------------------------------------
+// Strategy side
+// ---------------------------------------------------------------------------
+
+func (e *Engine) deliver(ev recorder.Event) {
+	switch {
+	case ev.Book != nil:
+		e.strategy.OnBook(e, *ev.Book)
+	case ev.PriceChange != nil:
+		e.strategy.OnPriceChange(e, *ev.PriceChange)
+	case ev.LastTrade != nil:
+		e.strategy.OnLastTrade(e, *ev.LastTrade)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Scheduling
+// ---------------------------------------------------------------------------
+
+type action struct {
+	at  time.Time
+	seq uint64
+	fn  func()
+}
+
+type actionQueue []action
+
+func (q actionQueue) Len() int { return len(q) }
+func (q actionQueue) Less(i, j int) bool {
+	if !q[i].at.Equal(q[j].at) {
+		return q[i].at.Before(q[j].at)
+	}
+	return q[i].seq < q[j].seq
+}
+func (q actionQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *actionQueue) Push(x any)   { *q = append(*q, x.(action)) }
+func (q *actionQueue) Pop() any {
+	old := *q
+	a := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return a
+}
+
+// schedule runs fn after delay from the current simulated time.
+func (e *Engine) schedule(delay time.Duration, fn func()) {
+	e.seq++
+	heap.Push(&e.queue, action{at: e.now.Add(delay), seq: e.seq, fn: fn})
+}
+
+// drain runs scheduled actions due at or before until, or all of them when
+// until is zero. Actions may schedule further actions.
+func (e *Engine) drain(until time.Time) {
+	for e.queue.Len() > 0 {
+		next := e.queue[0]
+		if !until.IsZero() && next.at.After(until) {
+			return
+		}
+		heap.Pop(&e.queue)
+		e.advance(next.at)
+		next.fn()
+	}
+}
+
+// advance moves the clock forward; it never moves backwards, so slightly
+// out-of-order receive times across streams are tolerated.
+func (e *Engine) advance(t time.Time) {
+	if t.After(e.now) {
+		e.now = t
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,175 @@
+package backtest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/recorder"
+	"github.com/lubluniky/clob-client-go/strategy"
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+// quoter bids once on the first book it sees and records fills.
+type quoter struct {
+	strategy.Base
+	price   decimal.Decimal
+	placed  string
+	placeAt time.Time
+	fills   []strategy.Fill
+	fillAt  []time.Time
+}
+
+func (q *quoter) OnBook(ex strategy.Exchange, b ws.BookUpdate) {
+	if q.placed != "" {
+		return
+	}
+	id, err := ex.PlaceOrder(strategy.Order{
+		TokenID: b.AssetID,
+		Side:    polymarket.Buy,
+		Price:   q.price,
+		Size:    decimal.NewFromInt(10),
+	})
+	if err != nil {
+		panic(err)
+	}
+	q.placed, q.placeAt = id, ex.Now()
+}
+
+func (q *quoter) OnFill(ex strategy.Exchange, f strategy.Fill) {
+	q.fills = append(q.fills, f)
+	q.fillAt = append(q.fillAt, ex.Now())
+}
+
+func writeLog(t *testing.T, events ...recorder.Event) *recorder.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	rec := recorder.New(nil, &buf)
+	for _, ev := range events {
+		if err := rec.Write(ev); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	r, err := recorder.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("reader: %v", err)
+	}
+	return r
+}
+
+func TestEngineQueuePositionAndLatency(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+
+	log := writeLog(t,
+		recorder.Event{Time: at(0), Type: ws.EventBook, Book: &ws.BookUpdate{
+			AssetID: "1",
+			Bids:    []ws.BookLevel{{Price: "0.48", Size: "100"}},
+			Asks:    []ws.BookLevel{{Price: "0.52", Size: "100"}},
+		}},
+		// Arrives before our order reaches the matcher: no effect on us.
+		recorder.Event{Time: at(20), Type: ws.EventLastTradePrice, LastTrade: &ws.LastTradePrice{
+			AssetID: "1", Price: "0.48", Size: "30", Side: "SELL",
+		}},
+		// Consumes 80 of the 100 ahead of us.
+		recorder.Event{Time: at(100), Type: ws.EventLastTradePrice, LastTrade: &ws.LastTradePrice{
+			AssetID: "1", Price: "0.48", Size: "80", Side: "SELL",
+		}},
+		// 20 ahead remain, so 4 of ours fill.
+		recorder.Event{Time: at(200), Type: ws.EventLastTradePrice, LastTrade: &ws.LastTradePrice{
+			AssetID: "1", Price: "0.48", Size: "24", Side: "SELL",
+		}},
+		// Trades through our price: the rest fills.
+		recorder.Event{Time: at(300), Type: ws.EventLastTradePrice, LastTrade: &ws.LastTradePrice{
+			AssetID: "1", Price: "0.47", Size: "50", Side: "SELL",
+		}},
+	)
+	defer log.Close()
+
+	q := &quoter{price: decimal.RequireFromString("0.48")}
+	engine := New(q, WithLatency(FixedLatency{FeedDelay: 10 * time.Millisecond, OrderDelay: 40 * time.Millisecond}))
+	res, err := engine.Run(log.Events())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if !q.placeAt.Equal(at(10)) {
+		t.Fatalf("strategy saw book at %v, want %v", q.placeAt, at(10))
+	}
+	if len(q.fills) != 2 {
+		t.Fatalf("expected 2 fills, got %+v", q.fills)
+	}
+	if !q.fills[0].Size.Equal(decimal.NewFromInt(4)) || !q.fills[1].Size.Equal(decimal.NewFromInt(6)) || !q.fills[0].Maker {
+		t.Fatalf("unexpected fills: %+v", q.fills)
+	}
+	if !q.fillAt[0].Equal(at(210)) {
+		t.Fatalf("fill delivered at %v, want %v", q.fillAt[0], at(210))
+	}
+	if got := res.Positions["1"]; !got.Equal(decimal.NewFromInt(10)) {
+		t.Fatalf("position mismatch: %s", got)
+	}
+	if !res.Cash.Equal(decimal.RequireFromString("-4.8")) {
+		t.Fatalf("cash mismatch: %s", res.Cash)
+	}
+	if !res.PnL().Equal(decimal.RequireFromString("0.2")) {
+		t.Fatalf("pnl mismatch: %s", res.PnL())
+	}
+}
+
+func TestEngineTakerFOKAndPostOnly(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	log := writeLog(t, recorder.Event{Time: t0, Type: ws.EventBook, Book: &ws.BookUpdate{
+		AssetID: "1",
+		Bids:    []ws.BookLevel{{Price: "0.40", Size: "10"}},
+		Asks:    []ws.BookLevel{{Price: "0.50", Size: "5"}, {Price: "0.60", Size: "5"}},
+	}})
+	defer log.Close()
+
+	s := &scripted{orders: []strategy.Order{
+		{TokenID: "1", Side: polymarket.Buy, Price: decimal.RequireFromString("0.5"), Size: decimal.NewFromInt(1), PostOnly: true},
+		{TokenID: "1", Side: polymarket.Buy, Price: decimal.RequireFromString("0.6"), Size: decimal.NewFromInt(20), Type: polymarket.FOK},
+		{TokenID: "1", Side: polymarket.Buy, Price: decimal.RequireFromString("0.6"), Size: decimal.NewFromInt(8), Type: polymarket.FAK},
+	}}
+	res, err := New(s, WithTakerFeeBps(100)).Run(log.Events())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if res.OrdersRejected != 1 || res.OrdersKilled != 1 {
+		t.Fatalf("rejected=%d killed=%d", res.OrdersRejected, res.OrdersKilled)
+	}
+	if len(res.Fills) != 2 || !res.Fills[0].Price.Equal(decimal.RequireFromString("0.5")) || !res.Fills[1].Size.Equal(decimal.NewFromInt(3)) {
+		t.Fatalf("unexpected fills: %+v", res.Fills)
+	}
+	// 1% of min(p, 1-p) per share: 5*0.5*0.01 + 3*0.4*0.01.
+	if !res.Fees.Equal(decimal.RequireFromString("0.037")) {
+		t.Fatalf("fees mismatch: %s", res.Fees)
+	}
+}
+
+// scripted places a fixed list of orders on the first book.
+type scripted struct {
+	strategy.Base
+	orders []strategy.Order
+}
+
+func (s *scripted) OnBook(ex strategy.Exchange, _ ws.BookUpdate) {
+	for _, o := range s.orders {
+		if _, err := ex.PlaceOrder(o); err != nil {
+			panic(err)
+		}
+	}
+	s.orders = nil
+}
+
+func TestJitterLatencyLiteralIsUsable(t *testing.T) {
+	l := &JitterLatency{Base: FixedLatency{OrderDelay: time.Millisecond}, Jitter: time.Millisecond}
+	if d := l.Order(); d < time.Millisecond || d >= 2*time.Millisecond {
+		t.Fatalf("order delay %s outside [1ms, 2ms)", d)
+	}
+}
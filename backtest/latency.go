@@ -0,0 +1,57 @@
+package backtest
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// LatencyModel supplies the delays the engine applies between the exchange
+// and the strategy. Each call returns the delay for one event, so models may
+// be random.
+type LatencyModel interface {
+	// Feed is the delay from the exchange publishing an event (market data or
+	// a fill) to the strategy receiving it.
+	Feed() time.Duration
+	// Order is the delay from PlaceOrder to the order reaching the matcher.
+	Order() time.Duration
+	// Cancel is the delay from CancelOrder to the cancel reaching the matcher.
+	Cancel() time.Duration
+}
+
+// FixedLatency applies constant delays. The zero value means no latency.
+type FixedLatency struct {
+	FeedDelay   time.Duration
+	OrderDelay  time.Duration
+	CancelDelay time.Duration
+}
+
+func (l FixedLatency) Feed() time.Duration   { return l.FeedDelay }
+func (l FixedLatency) Order() time.Duration  { return l.OrderDelay }
+func (l FixedLatency) Cancel() time.Duration { return l.CancelDelay }
+
+// JitterLatency adds uniform random jitter in [0, Jitter) to a fixed base.
+// A fixed seed makes runs reproducible; a struct literal uses seed 0.
+type JitterLatency struct {
+	Base   FixedLatency
+	Jitter time.Duration
+	rng    *rand.Rand
+}
+
+// NewJitterLatency returns a jittered model seeded with seed.
+func NewJitterLatency(base FixedLatency, jitter time.Duration, seed uint64) *JitterLatency {
+	return &JitterLatency{Base: base, Jitter: jitter, rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (l *JitterLatency) jitter() time.Duration {
+	if l.Jitter <= 0 {
+		return 0
+	}
+	if l.rng == nil {
+		l.rng = rand.New(rand.NewPCG(0, 0))
+	}
+	return time.Duration(l.rng.Int64N(int64(l.Jitter)))
+}
+
+func (l *JitterLatency) Feed() time.Duration   { return l.Base.FeedDelay + l.jitter() }
+func (l *JitterLatency) Order() time.Duration  { return l.Base.OrderDelay + l.jitter() }
+func (l *JitterLatency) Cancel() time.Duration { return l.Base.CancelDelay + l.jitter() }
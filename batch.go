@@ -0,0 +1,152 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/lubluniky/clob-client-go/internal/transport"
+)
+
+// MaxBatchSize is the most orders the exchange accepts in one POST /orders
+// request. PostOrders splits larger batches.
+const MaxBatchSize = 15
+
+// DefaultBatchConcurrency is how many batch chunks PostOrders posts at once
+// unless WithBatchConcurrency is given.
+const DefaultBatchConcurrency = 4
+
+// Order statuses reported for accepted orders.
+const (
+	OrderStatusLive      = "live"      // resting on the book
+	OrderStatusMatched   = "matched"   // matched on arrival
+	OrderStatusDelayed   = "delayed"   // marketable, held by the matching delay
+	OrderStatusUnmatched = "unmatched" // marketable but not matched after the delay
+)
+
+// WithBatchConcurrency sets how many chunks of an oversized PostOrders batch
+// are in flight at once. Default DefaultBatchConcurrency.
+func WithBatchConcurrency(n int) ClientOption {
+	return func(c *ClobClient) {
+		c.batchConcurrency = max(n, 1)
+	}
+}
+
+// OrderOutcome is the result of posting one order of a batch.
+type OrderOutcome struct {
+	// Index is the order's position in the PostOrders arguments.
+	Index int
+	// OrderID, Status and the amounts are set for accepted orders. Status is
+	// one of the OrderStatus constants.
+	OrderID            string
+	Status             string
+	TakingAmount       decimal.Decimal
+	MakingAmount       decimal.Decimal
+	TransactionsHashes []string
+	// Err is set when the order was rejected or its request failed. Exchange
+	// rejections are *APIError values classified like any other API error, so
+	// errors.Is(outcome.Err, ErrInsufficientBalance) works.
+	Err error
+}
+
+// Accepted reports whether the exchange accepted the order.
+func (o OrderOutcome) Accepted() bool {
+	return o.Err == nil
+}
+
+// BatchResult holds one outcome per order passed to PostOrders, in input
+// order.
+type BatchResult struct {
+	Outcomes []OrderOutcome
+}
+
+// Accepted returns the outcomes of the accepted orders.
+func (r *BatchResult) Accepted() []OrderOutcome {
+	var out []OrderOutcome
+	for _, o := range r.Outcomes {
+		if o.Accepted() {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Failed returns the outcomes of the orders that were not accepted.
+func (r *BatchResult) Failed() []OrderOutcome {
+	var out []OrderOutcome
+	for _, o := range r.Outcomes {
+		if !o.Accepted() {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Err joins the errors of all failed orders, each prefixed with its index,
+// or returns nil if every order was accepted.
+func (r *BatchResult) Err() error {
+	var errs []error
+	for _, o := range r.Outcomes {
+		if o.Err != nil {
+			errs = append(errs, fmt.Errorf("order %d: %w", o.Index, o.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fill records the outcomes of the n orders starting at start from the
+// chunk's responses or request error. Per-order errors, as left by
+// reconciliation, take precedence over err.
+func (r *BatchResult) fill(start, n int, resps []OrderResponse, errs []error, err error) {
+	// A chunk answered with a single rejection applies it to every order.
+	if err == nil && n > 1 && len(resps) == 1 && orderRejection(resps[0]) != nil {
+		err = orderRejection(resps[0])
+	}
+	for j := range n {
+		o := &r.Outcomes[start+j]
+		o.Index = start + j
+		switch {
+		case errs != nil:
+			if o.Err = errs[j]; o.Err == nil {
+				o.set(resps[j])
+			}
+		case err != nil:
+			o.Err = err
+		case j >= len(resps):
+			o.Err = fmt.Errorf("polymarket: no response for order %d of batch", o.Index)
+		default:
+			o.set(resps[j])
+		}
+	}
+}
+
+func (o *OrderOutcome) set(resp OrderResponse) {
+	if o.Err = orderRejection(resp); o.Err != nil {
+		return
+	}
+	resp.normalizeIDs()
+	o.OrderID = resp.OrderID
+	o.Status = strings.ToLower(resp.Status)
+	o.TakingAmount, _ = decimal.NewFromString(resp.TakingAmount)
+	o.MakingAmount, _ = decimal.NewFromString(resp.MakingAmount)
+	o.TransactionsHashes = resp.TransactionsHashes
+}
+
+// orderRejection returns the classified error for a response the exchange
+// did not accept, or nil.
+func orderRejection(resp OrderResponse) error {
+	if resp.Success && resp.ErrorMsg == "" {
+		return nil
+	}
+	msg := resp.ErrorMsg
+	if msg == "" {
+		msg = "order not accepted"
+		if resp.Status != "" {
+			msg += " (status " + resp.Status + ")"
+		}
+	}
+	return transport.NewRejection(http.MethodPost, EndpointPostOrders, http.StatusOK, msg)
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/lubluniky/clob-client-go/internal/transport"
+)
+
+// Endpoint groups used by WithCircuitBreaker.
+const (
+	// CircuitOrders covers order posts and heartbeats.
+	CircuitOrders = "orders"
+	// CircuitMarketData covers public market data reads.
+	CircuitMarketData = "market-data"
+	// CircuitAccount covers everything else: account, auth, trades,
+	// rewards and RFQ endpoints.
+	CircuitAccount = "account"
+	// CircuitExempt is the group of cancels, which bypass the breaker: pulling
+	// quotes must stay possible while order entry is degraded.
+	CircuitExempt = ""
+)
+
+// CircuitState is the state of an endpoint group's circuit breaker.
+type CircuitState = transport.CircuitState
+
+// Circuit breaker states.
+const (
+	CircuitClosed   = transport.CircuitClosed
+	CircuitOpen     = transport.CircuitOpen
+	CircuitHalfOpen = transport.CircuitHalfOpen
+)
+
+// CircuitBreakerConfig configures WithCircuitBreaker. Zero fields take the
+// defaults: open at a 50% failure rate over the last 50 attempts once at
+// least 20 were made, and probe again after 10s.
+type CircuitBreakerConfig = transport.BreakerConfig
+
+// WithCircuitBreaker fails requests fast with ErrCircuitOpen while the API is
+// degraded instead of letting every caller retry into it. Each endpoint
+// group (CircuitOrders, CircuitMarketData, CircuitAccount) has its own
+// circuit unless cfg.Group assigns groups differently; cancels are never
+// refused. Transitions are reported to observe.Hooks.OnCircuitChange, and
+// CircuitState reports the current state, e.g. to pull quotes while order
+// entry is unavailable.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) ClientOption {
+	return func(c *ClobClient) {
+		if cfg.Group == nil {
+			cfg.Group = EndpointGroup
+		}
+		c.breaker = &cfg
+	}
+}
+
+// CircuitState returns the state of an endpoint group's circuit. It is
+// CircuitClosed when no breaker is configured.
+func (c *ClobClient) CircuitState(group string) CircuitState {
+	return c.http.CircuitState(group)
+}
+
+// marketDataPaths are the public market data endpoints, excluding the
+// per-market paths matched by prefix in EndpointGroup.
+var marketDataPaths = map[string]bool{
+	EndpointTime:                      true,
+	EndpointMarkets:                   true,
+	EndpointSimplifiedMarkets:         true,
+	EndpointSamplingSimplifiedMarkets: true,
+	EndpointSamplingMarkets:           true,
+	EndpointOrderBook:                 true,
+	EndpointOrderBooks:                true,
+	EndpointMidpoint:                  true,
+	EndpointMidpoints:                 true,
+	EndpointPrice:                     true,
+	EndpointPrices:                    true,
+	EndpointSpread:                    true,
+	EndpointSpreads:                   true,
+	EndpointLastTradePrice:            true,
+	EndpointLastTradesPrices:          true,
+	EndpointTickSize:                  true,
+	EndpointNegRisk:                   true,
+	EndpointFeeRate:                   true,
+	EndpointPriceHistory:              true,
+}
+
+// EndpointGroup returns the circuit breaker group of a request, CircuitExempt
+// for cancels.
+func EndpointGroup(method, path string) string {
+	switch {
+	case method == http.MethodDelete && (path == EndpointCancelOrder || path == EndpointCancelOrders),
+		path == EndpointCancelAll, path == EndpointCancelMarketOrders:
+		return CircuitExempt
+	case method == http.MethodPost && (path == EndpointPostOrder || path == EndpointPostOrders),
+		path == EndpointHeartbeat:
+		return CircuitOrders
+	case marketDataPaths[path], strings.HasPrefix(path, EndpointMarket):
+		return CircuitMarketData
+	}
+	return CircuitAccount
+}
@@ -15,6 +15,8 @@ import (
 
 	"github.com/lubluniky/clob-client-go/internal/signing"
 	"github.com/lubluniky/clob-client-go/internal/transport"
+	"github.com/lubluniky/clob-client-go/observe"
+	"github.com/lubluniky/clob-client-go/replay"
 )
 
 // DefaultBaseURL is the production Polymarket CLOB API base URL.
@@ -32,17 +34,50 @@ type ClobClient struct {
 	signer  *ecdsa.PrivateKey
 	address common.Address
 	funder  *common.Address
+	// skipFunderCheck disables funder validation; see WithFunderValidation.
+	skipFunderCheck bool
 
-	// L2 auth (optional)
-	creds *ApiCreds
+	// L2 auth (optional), guarded by credsMu so it can be rotated while
+	// requests and streams are in flight.
+	credsMu sync.RWMutex
+	creds   *ApiCreds
 
 	// HTTP transport options (applied in constructor)
 	httpOpts []transport.Option
+	hooks    observe.Hooks
+	recorder *replay.Recorder
+	replayer *replay.Replayer
+	breaker  *CircuitBreakerConfig
+
+	// Server clock sync (optional)
+	clock *ClockSync
+
+	// Order preflight (optional)
+	balancePreflight bool
+	preflightRefresh bool
+
+	// Resends allowed after an order post with an unknown outcome.
+	orderResends int
+	// Chunks of an oversized PostOrders batch posted at once.
+	batchConcurrency int
+
+	// Request body serialization; see WithEncoder.
+	encoder Encoder
+
+	// Hedged and fanned-out market data reads; see reads.go.
+	hedge          *hedger
+	fanOutChunk    int
+	fanOutParallel int
+
+	// Internal caches; meta is shared by the clients of a ClientPool.
+	meta        *metadata
+	tickSizeTTL time.Duration
+}
 
-	// Internal caches
+// metadata caches per-token market metadata.
+type metadata struct {
 	tickSizes       sync.Map // token_id -> string (tick size)
 	tickSizesLoaded sync.Map // token_id -> time.Time
-	tickSizeTTL     time.Duration
 	negRisk         sync.Map // token_id -> bool
 	feeRates        sync.Map // token_id -> string
 }
@@ -125,19 +160,66 @@ func WithHTTPOptions(opts ...transport.Option) ClientOption {
 	}
 }
 
+// WithHooks installs instrumentation hooks on the underlying HTTP client. See
+// the observe package for slog, tracing and metrics adapters.
+func WithHooks(h observe.Hooks) ClientOption {
+	return func(c *ClobClient) {
+		c.hooks = h
+	}
+}
+
+// WithRecorder records every HTTP exchange to rec for later replay. See the
+// replay package.
+func WithRecorder(rec *replay.Recorder) ClientOption {
+	return func(c *ClobClient) {
+		c.recorder = rec
+	}
+}
+
+// WithReplay serves every HTTP request from a recording instead of the network.
+func WithReplay(rp *replay.Replayer) ClientOption {
+	return func(c *ClobClient) {
+		c.replayer = rp
+	}
+}
+
 // NewClobClient creates a new Polymarket CLOB client.
 func NewClobClient(opts ...ClientOption) *ClobClient {
 	c := &ClobClient{
-		baseURL:       DefaultBaseURL,
-		chainID:       PolygonChainID,
-		signatureType: EOA,
-		tickSizeTTL:   time.Minute,
+		baseURL:          DefaultBaseURL,
+		chainID:          PolygonChainID,
+		signatureType:    EOA,
+		tickSizeTTL:      time.Minute,
+		orderResends:     1,
+		batchConcurrency: DefaultBatchConcurrency,
+		encoder:          json.Marshal,
+		fanOutParallel:   1,
+		meta:             &metadata{},
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.deriveFunder()
+	if c.http != nil {
+		return c // shares a ClientPool's HTTP client
+	}
 	// Initialize HTTP client with final baseURL and any transport options.
-	c.http = transport.NewHTTPClient(c.baseURL, c.httpOpts...)
+	// Caller options come after the default retry policies so they can
+	// override them.
+	httpOpts := append(defaultRetryPolicies(), c.httpOpts...)
+	if c.hooks != nil {
+		httpOpts = append(httpOpts, transport.WithHooks(c.hooks))
+	}
+	if c.breaker != nil {
+		httpOpts = append(httpOpts, transport.WithCircuitBreaker(*c.breaker))
+	}
+	if c.replayer != nil {
+		httpOpts = append(httpOpts, transport.WithReplay(c.replayer))
+	}
+	if c.recorder != nil {
+		httpOpts = append(httpOpts, transport.WithRecorder(c.recorder))
+	}
+	c.http = transport.NewHTTPClient(c.baseURL, httpOpts...)
 	return c
 }
 
@@ -146,9 +228,35 @@ func (c *ClobClient) Address() string {
 	return c.address.Hex()
 }
 
-// SetApiCreds updates the L2 API credentials on the client.
+// SetApiCreds updates the L2 API credentials on the client. It is safe to
+// call while requests are in flight; ws clients built with
+// ws.WithCredsFrom pick the new credentials up on their next reconnect.
 func (c *ClobClient) SetApiCreds(creds ApiCreds) {
+	c.credsMu.Lock()
 	c.creds = &creds
+	c.credsMu.Unlock()
+}
+
+// ApiCreds returns a copy of the current L2 API credentials, or nil if none
+// are set.
+func (c *ClobClient) ApiCreds() *ApiCreds {
+	c.credsMu.RLock()
+	defer c.credsMu.RUnlock()
+	if c.creds == nil {
+		return nil
+	}
+	creds := *c.creds
+	return &creds
+}
+
+// Warm opens up to conns connections to the API host ahead of the first
+// request. It is most useful with transport.WithLowLatency, whose idle pool
+// keeps the connections open between orders.
+func (c *ClobClient) Warm(ctx context.Context, conns int) error {
+	if err := c.http.Warm(ctx, conns); err != nil {
+		return fmt.Errorf("polymarket: warming connections: %w", err)
+	}
+	return nil
 }
 
 // SetSignatureType updates the default signature type for order-related
@@ -171,24 +279,28 @@ func (c *ClobClient) l1Headers(nonce int) (http.Header, error) {
 	if c.signer == nil {
 		return nil, &AuthError{Message: "signer key required for L1 authentication"}
 	}
-	return signing.BuildL1Headers(c.signer, c.chainID, nonce)
+	return signing.BuildL1HeadersAt(c.signer, c.chainID, nonce, c.Now())
 }
 
-// l2Headers returns HMAC-signed headers for L2 requests.
-func (c *ClobClient) l2Headers(method, path, body string) (http.Header, error) {
-	if c.creds == nil {
+// l2Headers returns HMAC-signed headers for L2 requests, signed with apiCreds
+// or, if nil, the client's current credentials.
+func (c *ClobClient) l2Headers(apiCreds *ApiCreds, method, path, body string) (http.Header, error) {
+	if apiCreds == nil {
+		apiCreds = c.ApiCreds()
+	}
+	if apiCreds == nil {
 		return nil, &AuthError{Message: "API credentials required for L2 authentication"}
 	}
 	if c.address == (common.Address{}) {
 		return nil, &AuthError{Message: "address required for L2 authentication; use WithSigner(...) or WithAddress(...)"}
 	}
 	creds := signing.L2Credentials{
-		ApiKey:        c.creds.ApiKey,
-		ApiSecret:     c.creds.ApiSecret,
-		ApiPassphrase: c.creds.ApiPassphrase,
+		ApiKey:        apiCreds.ApiKey,
+		ApiSecret:     apiCreds.ApiSecret,
+		ApiPassphrase: apiCreds.ApiPassphrase,
 		Address:       c.address.Hex(),
 	}
-	return signing.BuildL2Headers(creds, method, path, body)
+	return signing.BuildL2HeadersAt(creds, method, path, body, c.Now())
 }
 
 // ---------------------------------------------------------------------------
@@ -197,74 +309,7 @@ func (c *ClobClient) l2Headers(method, path, body string) (http.Header, error) {
 
 // getJSON is a convenience helper for L0 GET requests that returns parsed JSON.
 func (c *ClobClient) getJSON(ctx context.Context, path string, query map[string]string) (json.RawMessage, error) {
-	resp, err := c.http.Get(ctx, path, c.l0Headers(), query)
-	if err != nil {
-		return nil, err
-	}
-	body, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-	return json.RawMessage(body), nil
-}
-
-// getL2JSON is a convenience helper for authenticated GET requests.
-func (c *ClobClient) getL2JSON(ctx context.Context, path string, query map[string]string) (json.RawMessage, error) {
-	headers, err := c.l2Headers("GET", path, "")
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.http.Get(ctx, path, headers, query)
-	if err != nil {
-		return nil, err
-	}
-	body, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-	return json.RawMessage(body), nil
-}
-
-// postL2JSON posts JSON body with L2 auth and returns parsed response.
-func (c *ClobClient) postL2JSON(ctx context.Context, path string, reqBody interface{}) (json.RawMessage, error) {
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("polymarket: marshalling body: %w", err)
-	}
-	headers, err := c.l2Headers("POST", path, string(bodyBytes))
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.http.Post(ctx, path, headers, reqBody)
-	if err != nil {
-		return nil, err
-	}
-	body, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-	return json.RawMessage(body), nil
-}
-
-// deleteL2JSON sends a DELETE with L2 auth and returns parsed response.
-func (c *ClobClient) deleteL2JSON(ctx context.Context, path string, reqBody interface{}) (json.RawMessage, error) {
-	var bodyStr string
-	if reqBody != nil {
-		bodyBytes, err := json.Marshal(reqBody)
-		if err != nil {
-			return nil, fmt.Errorf("polymarket: marshalling body: %w", err)
-		}
-		bodyStr = string(bodyBytes)
-	}
-	headers, err := c.l2Headers("DELETE", path, bodyStr)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.http.Delete(ctx, path, headers, reqBody)
-	if err != nil {
-		return nil, err
-	}
-	body, err := transport.ParseResponse(resp)
+	body, err := c.send(ctx, apiRequest{method: http.MethodGet, path: path, query: query})
 	if err != nil {
 		return nil, err
 	}
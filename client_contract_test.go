@@ -5,17 +5,27 @@ import (
 	"crypto/ecdsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/shopspring/decimal"
+
+	"github.com/lubluniky/clob-client-go/internal/signing"
+	"github.com/lubluniky/clob-client-go/internal/transport"
+	"github.com/lubluniky/clob-client-go/observe"
+	"github.com/lubluniky/clob-client-go/replay"
 )
 
 func testSigner(t *testing.T) *ecdsa.PrivateKey {
@@ -133,6 +143,92 @@ func TestPostOrdersPayload(t *testing.T) {
 	}
 }
 
+func TestPostOrdersSplitsBatchAndReportsOutcomes(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload []struct {
+			Order SignedOrder `json:"order"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("unmarshal body: %v", err)
+		}
+		mu.Lock()
+		sizes = append(sizes, len(payload))
+		mu.Unlock()
+
+		resps := make([]map[string]any, len(payload))
+		for i, p := range payload {
+			if p.Order.Salt == "3" {
+				resps[i] = map[string]any{"success": false, "errorMsg": "not enough balance / allowance"}
+				continue
+			}
+			resps[i] = map[string]any{"success": true, "orderID": "o" + p.Order.Salt, "status": "matched", "takingAmount": "1.5", "makingAmount": "0.75"}
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(WithBaseURL(srv.URL), WithSigner(testSigner(t)), WithCreds(testCreds()))
+	args := make([]PostOrdersArgs, 20)
+	for i := range args {
+		order := sampleSignedOrder()
+		order.Salt = strconv.Itoa(i)
+		args[i] = PostOrdersArgs{Order: order, OrderType: GTC}
+	}
+
+	result, err := client.PostOrders(context.Background(), args, false, false)
+	if err != nil {
+		t.Fatalf("post orders: %v", err)
+	}
+	if len(sizes) != 2 || sizes[0]+sizes[1] != 20 || max(sizes[0], sizes[1]) != MaxBatchSize {
+		t.Fatalf("unexpected chunk sizes %v", sizes)
+	}
+	if len(result.Outcomes) != 20 || len(result.Accepted()) != 19 {
+		t.Fatalf("unexpected outcomes %+v", result.Outcomes)
+	}
+	for i, o := range result.Outcomes {
+		if o.Index != i {
+			t.Fatalf("outcome %d has index %d", i, o.Index)
+		}
+		if i == 3 {
+			continue
+		}
+		if o.OrderID != "o"+strconv.Itoa(i) || o.Status != OrderStatusMatched || !o.TakingAmount.Equal(decimal.RequireFromString("1.5")) {
+			t.Fatalf("unexpected outcome %+v", o)
+		}
+	}
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].Index != 3 || !errors.Is(failed[0].Err, ErrInsufficientBalance) {
+		t.Fatalf("unexpected failures %+v", failed)
+	}
+	if err := result.Err(); !errors.Is(err, ErrInsufficientBalance) || !strings.Contains(err.Error(), "order 3:") {
+		t.Fatalf("unexpected batch error %v", err)
+	}
+}
+
+func TestPostOrdersReturnsRequestErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"Unauthorized/Invalid api key"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(WithBaseURL(srv.URL), WithSigner(testSigner(t)), WithCreds(testCreds()))
+	args := make([]PostOrdersArgs, MaxBatchSize+1)
+	for i := range args {
+		args[i] = PostOrdersArgs{Order: sampleSignedOrder(), OrderType: GTC}
+	}
+	result, err := client.PostOrders(context.Background(), args, false, false)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected request error, got %v", err)
+	}
+	if result == nil || len(result.Failed()) != len(args) || !errors.Is(result.Outcomes[MaxBatchSize].Err, ErrUnauthorized) {
+		t.Fatalf("every order should carry the request error: %+v", result)
+	}
+}
+
 func TestCancelPayloads(t *testing.T) {
 	var cancelOrder map[string]any
 	var cancelOrders []string
@@ -230,6 +326,18 @@ func TestAccountPayloads(t *testing.T) {
 		t.Fatalf("expected empty notifications body, got %q", notificationsBody)
 	}
 
+	// A query map next to a raw query is appended without escaping the raw
+	// part.
+	if _, err := client.send(context.Background(), apiRequest{
+		method: http.MethodDelete, path: EndpointNotifications, auth: authL2,
+		rawQuery: "ids=1,2", query: map[string]string{"note": "a b"},
+	}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if notificationsQuery != "ids=1,2&note=a+b" {
+		t.Fatalf("merged query mismatch: %s", notificationsQuery)
+	}
+
 	if err := client.PostHeartbeat(context.Background(), "hb-1"); err != nil {
 		t.Fatalf("post heartbeat: %v", err)
 	}
@@ -541,7 +649,10 @@ func TestBalanceAllowanceQueries(t *testing.T) {
 
 func TestCreateOrderUsesClientSignatureTypeAndFunder(t *testing.T) {
 	key := testSigner(t)
-	funder := common.HexToAddress("0x00000000000000000000000000000000000000F0")
+	funder, err := DeriveProxyWallet(crypto.PubkeyToAddress(key.PublicKey), PolygonChainID)
+	if err != nil {
+		t.Fatalf("derive proxy wallet: %v", err)
+	}
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
@@ -734,3 +845,1266 @@ func TestBuilderEndpointsContracts(t *testing.T) {
 		t.Fatalf("request sequence mismatch:\n got: %v\nwant: %v", seen, expected)
 	}
 }
+
+func TestCreateAndPostOrderBalancePreflight(t *testing.T) {
+	key := testSigner(t)
+	posted := 0
+	updated := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EndpointTickSize:
+			_, _ = w.Write([]byte(`{"minimum_tick_size":0.01}`))
+		case EndpointNegRisk:
+			_, _ = w.Write([]byte(`{"neg_risk":false}`))
+		case EndpointFeeRate:
+			_, _ = w.Write([]byte(`{"base_fee":0}`))
+		case EndpointOrders:
+			// 10 shares resting at 0.5 lock 5 USDC of collateral.
+			_, _ = w.Write([]byte(`{"data":[{"id":"o1","side":"BUY","original_size":"12","size_matched":"2","price":"0.5"}],"next_cursor":"LTE="}`))
+		case EndpointBalanceAllowance:
+			if got := r.URL.Query().Get("asset_type"); got != string(AssetTypeCollateral) {
+				t.Fatalf("asset_type mismatch: %s", got)
+			}
+			if updated == 0 {
+				_, _ = w.Write([]byte(`{"balance":"9000000","allowance":"100000000"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"balance":"20000000","allowance":"100000000"}`))
+		case EndpointUpdateBalanceAllowance:
+			updated++
+			_, _ = w.Write([]byte(`{}`))
+		case EndpointPostOrder:
+			posted++
+			_, _ = w.Write([]byte(`{"orderID":"new","status":"live"}`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	args := OrderArgs{
+		TokenID: "1",
+		Price:   decimal.RequireFromString("0.5"),
+		Size:    decimal.RequireFromString("10"),
+		Side:    Buy,
+	}
+
+	strict := NewClobClient(WithBaseURL(srv.URL), WithSigner(key), WithCreds(testCreds()), WithBalancePreflight(false))
+	_, err := strict.CreateAndPostOrder(context.Background(), args, GTC, false)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Field != "balance" {
+		t.Fatalf("expected balance validation error, got: %v", err)
+	}
+	if posted != 0 || updated != 0 {
+		t.Fatalf("expected no post/update, got posted=%d updated=%d", posted, updated)
+	}
+
+	refresh := NewClobClient(WithBaseURL(srv.URL), WithSigner(key), WithCreds(testCreds()), WithBalancePreflight(true))
+	resp, err := refresh.CreateAndPostOrder(context.Background(), args, GTC, false)
+	if err != nil {
+		t.Fatalf("create and post with refresh: %v", err)
+	}
+	if resp.ID != "new" || posted != 1 || updated != 1 {
+		t.Fatalf("unexpected result: resp=%+v posted=%d updated=%d", resp, posted, updated)
+	}
+}
+
+func TestClockSyncCorrectsAuthTimestamps(t *testing.T) {
+	const skew = time.Hour
+	var gotTimestamp string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EndpointTime:
+			_, _ = w.Write([]byte(strconv.FormatInt(time.Now().Add(skew).Unix(), 10)))
+		case EndpointClosedOnly:
+			gotTimestamp = r.Header.Get("POLY_TIMESTAMP")
+			_, _ = w.Write([]byte(`{"closed_only":false}`))
+		case EndpointTickSize:
+			_, _ = w.Write([]byte(`{"minimum_tick_size":0.01}`))
+		case EndpointNegRisk:
+			_, _ = w.Write([]byte(`{"neg_risk":false}`))
+		case EndpointFeeRate:
+			_, _ = w.Write([]byte(`{"base_fee":0}`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	hooks := &recordingHooks{}
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithSigner(testSigner(t)),
+		WithCreds(testCreds()),
+		WithClockSync(time.Minute),
+		WithHooks(hooks),
+	)
+	if err := client.Clock().Sync(context.Background()); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	hooks.mu.Lock()
+	if len(hooks.clocks) != 1 || hooks.clocks[0].Err != nil || hooks.clocks[0].Offset != client.Clock().Offset() {
+		t.Fatalf("clock sync not reported: %+v", hooks.clocks)
+	}
+	hooks.mu.Unlock()
+
+	stats := client.Clock().Stats()
+	if diff := stats.Offset - skew; diff < -time.Second || diff > time.Second {
+		t.Fatalf("offset mismatch: got %s want ~%s", stats.Offset, skew)
+	}
+	if stats.Syncs != 1 || stats.LastSync.IsZero() {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	if _, err := client.GetClosedOnlyMode(context.Background()); err != nil {
+		t.Fatalf("closed only: %v", err)
+	}
+	ts, err := strconv.ParseInt(gotTimestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("parse timestamp %q: %v", gotTimestamp, err)
+	}
+	if want := time.Now().Add(skew).Unix(); ts < want-2 || ts > want+2 {
+		t.Fatalf("auth timestamp not corrected: got %d want ~%d", ts, want)
+	}
+
+	exp := client.GTDExpiration(time.Minute)
+	if want := time.Now().Add(skew + time.Minute).Unix(); int64(exp) < want-2 || int64(exp) > want+2 {
+		t.Fatalf("gtd expiration not corrected: got %d want ~%d", exp, want)
+	}
+
+	order, err := client.CreateOrder(context.Background(), OrderArgs{
+		TokenID: "1",
+		Price:   decimal.RequireFromString("0.5"),
+		Size:    decimal.RequireFromString("10"),
+		Side:    Buy,
+		TTL:     time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	got, _ := strconv.ParseInt(order.Expiration, 10, 64)
+	if want := time.Now().Add(skew + time.Minute).Unix(); got < want-2 || got > want+2 {
+		t.Fatalf("order expiration not corrected: got %d want ~%d", got, want)
+	}
+}
+
+type recordingHooks struct {
+	observe.Nop
+	mu        sync.Mutex
+	requests  []observe.Request
+	retries   []observe.Retry
+	responses []observe.Response
+	circuits  []observe.CircuitChange
+	clocks    []observe.ClockSync
+}
+
+func (h *recordingHooks) OnRequest(ctx context.Context, req observe.Request) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = append(h.requests, req)
+	return ctx
+}
+
+func (h *recordingHooks) OnRetry(_ context.Context, retry observe.Retry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.retries = append(h.retries, retry)
+}
+
+func (h *recordingHooks) OnResponse(_ context.Context, resp observe.Response) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.responses = append(h.responses, resp)
+}
+
+func (h *recordingHooks) OnCircuitChange(ev observe.CircuitChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.circuits = append(h.circuits, ev)
+}
+
+func (h *recordingHooks) OnClockSync(ev observe.ClockSync) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clocks = append(h.clocks, ev)
+}
+
+func TestHooksObserveRetriesWithRedactedHeaders(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"closed_only":false}`))
+	}))
+	defer srv.Close()
+
+	hooks := &recordingHooks{}
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithSigner(testSigner(t)),
+		WithCreds(testCreds()),
+		WithHooks(hooks),
+		WithHTTPOptions(transport.WithBaseDelay(time.Millisecond)),
+	)
+	if _, err := client.GetClosedOnlyMode(context.Background()); err != nil {
+		t.Fatalf("closed only: %v", err)
+	}
+
+	if len(hooks.requests) != 2 || len(hooks.responses) != 2 || len(hooks.retries) != 1 {
+		t.Fatalf("unexpected hook counts: req=%d resp=%d retry=%d", len(hooks.requests), len(hooks.responses), len(hooks.retries))
+	}
+	if hooks.retries[0].StatusCode != http.StatusServiceUnavailable || hooks.responses[1].StatusCode != http.StatusOK {
+		t.Fatalf("unexpected statuses: retry=%+v resp=%+v", hooks.retries[0], hooks.responses[1])
+	}
+	for _, req := range hooks.requests {
+		if got := req.Header.Get("POLY_API_KEY"); got != observe.Redacted {
+			t.Fatalf("api key not redacted: %q", got)
+		}
+		if got := req.Header.Get("POLY_SIGNATURE"); got != observe.Redacted {
+			t.Fatalf("signature not redacted: %q", got)
+		}
+	}
+	if hooks.requests[1].Attempt != 1 {
+		t.Fatalf("attempt mismatch: %d", hooks.requests[1].Attempt)
+	}
+}
+
+func TestLowLatencyAttemptTimeoutAndPhaseTimings(t *testing.T) {
+	var timeCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == EndpointTime && timeCalls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte(`1700000000`))
+	}))
+	defer srv.Close()
+
+	hooks := &recordingHooks{}
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithHooks(hooks),
+		WithHTTPOptions(
+			transport.WithLowLatency(),
+			transport.WithBaseDelay(time.Millisecond),
+			transport.WithEndpointTimeout(http.MethodGet, EndpointTime, 100*time.Millisecond),
+		),
+	)
+	if err := client.Warm(context.Background(), 1); err != nil {
+		t.Fatalf("warm: %v", err)
+	}
+	if _, err := client.GetServerTime(context.Background()); err != nil {
+		t.Fatalf("server time: %v", err)
+	}
+
+	if timeCalls.Load() != 2 || len(hooks.retries) != 1 || len(hooks.responses) != 2 {
+		t.Fatalf("expected one timed-out attempt and a retry: calls=%d retries=%d responses=%d", timeCalls.Load(), len(hooks.retries), len(hooks.responses))
+	}
+	first, last := hooks.responses[0], hooks.responses[1]
+	if first.Err == nil || !first.Timings.Reused {
+		t.Fatalf("first attempt should time out on the warmed connection: %+v", first)
+	}
+	if last.StatusCode != http.StatusOK || last.Timings.TTFB <= 0 {
+		t.Fatalf("missing timings on success: %+v", last)
+	}
+}
+
+func TestHedgedReadFirstResponseWins(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done() // stalls until the hedge wins and cancels it
+			return
+		}
+		_, _ = w.Write([]byte(`{"mid":"0.55"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(WithBaseURL(srv.URL), WithHedging(0.95, 20*time.Millisecond))
+	start := time.Now()
+	mid, err := client.GetMidpoint(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("midpoint: %v", err)
+	}
+	if !mid.Equal(decimal.RequireFromString("0.55")) || calls.Load() != 2 {
+		t.Fatalf("unexpected result: mid=%s calls=%d", mid, calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("hedge did not fire: %v", elapsed)
+	}
+}
+
+func TestHedgedReadSamplesLosingPrimary(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"mid":"0.55"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(WithBaseURL(srv.URL), WithHedging(0.95, 0))
+	if client.hedge.initial != DefaultHedgeDelay {
+		t.Fatalf("zero initial delay not replaced: %v", client.hedge.initial)
+	}
+	if _, err := client.GetMidpoint(context.Background(), "tok"); err != nil {
+		t.Fatalf("midpoint: %v", err)
+	}
+
+	// The primary lost, so its sample is recorded after the call returns.
+	var samples []time.Duration
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		client.hedge.mu.Lock()
+		if w := client.hedge.latencies[EndpointMidpoint]; w != nil {
+			samples = slices.Clone(w.samples)
+		}
+		client.hedge.mu.Unlock()
+		if len(samples) > 0 {
+			break
+		}
+	}
+	if len(samples) != 1 || samples[0] < DefaultHedgeDelay {
+		t.Fatalf("expected one sample of the losing primary, got %v", samples)
+	}
+}
+
+func TestReadFanOutChunksWithBoundedParallelism(t *testing.T) {
+	var inFlight, peak, calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		var params []BookParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil || len(params) > 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		out := map[string]string{}
+		for _, p := range params {
+			out[p.TokenID] = "0.5"
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(WithBaseURL(srv.URL), WithReadFanOut(3, 2))
+	tokens := []string{"a", "b", "c", "d", "e", "f", "g"}
+	mids, err := client.GetMidpoints(context.Background(), tokens)
+	if err != nil {
+		t.Fatalf("midpoints: %v", err)
+	}
+	if len(mids) != len(tokens) || calls.Load() != 3 {
+		t.Fatalf("unexpected fan-out: mids=%v calls=%d", mids, calls.Load())
+	}
+	if peak.Load() > 2 {
+		t.Fatalf("parallelism exceeded: %d", peak.Load())
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"mid":"0.5"}`))
+	}))
+	defer srv.Close()
+
+	hooks := &recordingHooks{}
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithHooks(hooks),
+		WithCircuitBreaker(CircuitBreakerConfig{MinRequests: 4, Window: 4, CoolDown: 50 * time.Millisecond}),
+		WithHTTPOptions(transport.WithBaseDelay(time.Millisecond), transport.WithMaxRetries(5)),
+	)
+	ctx := context.Background()
+
+	_, err := client.GetMidpoint(ctx, "tok")
+	if !errors.Is(err, ErrCircuitOpen) || calls.Load() != 4 {
+		t.Fatalf("circuit should open mid-retry after 4 failures: err=%v calls=%d", err, calls.Load())
+	}
+	if client.CircuitState(CircuitMarketData) != CircuitOpen || client.CircuitState(CircuitOrders) != CircuitClosed {
+		t.Fatalf("unexpected states: market-data=%s orders=%s", client.CircuitState(CircuitMarketData), client.CircuitState(CircuitOrders))
+	}
+	var openErr *CircuitOpenError
+	if _, err := client.GetPrice(ctx, "tok", Buy); !errors.As(err, &openErr) || openErr.Group != CircuitMarketData || calls.Load() != 4 {
+		t.Fatalf("open circuit should fail fast: err=%v calls=%d", err, calls.Load())
+	}
+
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.GetMidpoint(ctx, "tok"); err != nil {
+		t.Fatalf("probe after cool-down: %v", err)
+	}
+	if client.CircuitState(CircuitMarketData) != CircuitClosed {
+		t.Fatalf("circuit should close after a successful probe")
+	}
+	var transitions []string
+	for _, ev := range hooks.circuits {
+		transitions = append(transitions, ev.From+">"+ev.To)
+	}
+	if got := strings.Join(transitions, ","); got != "closed>open,open>half-open,half-open>closed" {
+		t.Fatalf("unexpected transitions: %s", got)
+	}
+}
+
+func TestCircuitBreakerLetsCancelsThrough(t *testing.T) {
+	var cancels atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EndpointCancelAll, EndpointCancelOrder:
+			cancels.Add(1)
+			_, _ = w.Write([]byte(`{"canceled":[],"not_canceled":{}}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithSigner(testSigner(t)),
+		WithCreds(testCreds()),
+		WithCircuitBreaker(CircuitBreakerConfig{MinRequests: 2, Window: 2, CoolDown: time.Minute}),
+		WithHTTPOptions(transport.WithBaseDelay(time.Millisecond), transport.WithMaxRetries(1)),
+	)
+	ctx := context.Background()
+
+	if err := client.PostHeartbeat(ctx, ""); !errors.Is(err, ErrServer) {
+		t.Fatalf("expected server error, got %v", err)
+	}
+	if err := client.PostHeartbeat(ctx, ""); !errors.Is(err, ErrCircuitOpen) || client.CircuitState(CircuitOrders) != CircuitOpen {
+		t.Fatalf("orders circuit should be open: err=%v state=%s", err, client.CircuitState(CircuitOrders))
+	}
+	if err := client.CancelAll(ctx); err != nil {
+		t.Fatalf("cancel all with open orders circuit: %v", err)
+	}
+	if err := client.CancelOrder(ctx, "0xabc"); err != nil {
+		t.Fatalf("cancel order with open orders circuit: %v", err)
+	}
+	if cancels.Load() != 2 {
+		t.Fatalf("expected both cancels to reach the server, got %d", cancels.Load())
+	}
+}
+
+func TestClientPoolSharesCachesAndAggregates(t *testing.T) {
+	alice, bob := testSigner(t), testSigner(t)
+	bobAddr := crypto.PubkeyToAddress(bob.PublicKey).Hex()
+	var tickCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr := r.Header.Get("POLY_ADDRESS")
+		switch r.URL.Path {
+		case EndpointTickSize:
+			tickCalls.Add(1)
+			_, _ = w.Write([]byte(`{"minimum_tick_size":0.01}`))
+		case EndpointCancelAll:
+			if addr == bobAddr {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error":"closed only"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"canceled":[]}`))
+		case EndpointOrders:
+			_, _ = w.Write([]byte(`{"data":[{"id":"` + addr + `"}],"next_cursor":"LTE="}`))
+		case EndpointBalanceAllowance:
+			balance := "1000000"
+			if addr == bobAddr {
+				balance = "2500000"
+			}
+			_, _ = w.Write([]byte(`{"balance":"` + balance + `","allowance":"0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	pool := NewClientPool(WithBaseURL(srv.URL))
+	creds := testCreds()
+	a, err := pool.Add(Account{ID: "alice", Signer: alice, Creds: &creds})
+	if err != nil {
+		t.Fatalf("add alice: %v", err)
+	}
+	b, err := pool.Add(Account{ID: "bob", Signer: bob, Creds: &creds, SignatureType: PolyProxy})
+	if err != nil {
+		t.Fatalf("add bob: %v", err)
+	}
+	if _, err := pool.Add(Account{ID: "bob", Signer: bob}); err == nil {
+		t.Fatalf("duplicate account accepted")
+	}
+	if a.http != b.http || a.Address() == b.Address() || b.signatureType != PolyProxy {
+		t.Fatalf("accounts should share transport but not auth")
+	}
+
+	ctx := context.Background()
+	if _, err := a.GetTickSize(ctx, "tok"); err != nil {
+		t.Fatalf("tick size: %v", err)
+	}
+	if _, err := b.GetTickSize(ctx, "tok"); err != nil || tickCalls.Load() != 1 {
+		t.Fatalf("tick size cache not shared: err=%v calls=%d", err, tickCalls.Load())
+	}
+
+	err = pool.CancelAll(ctx)
+	if !errors.Is(err, ErrForbidden) || !strings.Contains(err.Error(), "account bob") || strings.Contains(err.Error(), "account alice") {
+		t.Fatalf("unexpected cancel-all error: %v", err)
+	}
+
+	orders, err := pool.OpenOrders(ctx, OpenOrderParams{})
+	if err != nil || len(orders) != 2 || orders["bob"][0].ID != bobAddr {
+		t.Fatalf("unexpected open orders: %v %v", orders, err)
+	}
+	balances, err := pool.Balances(ctx, BalanceAllowanceParams{AssetType: string(AssetTypeCollateral)})
+	if err != nil || !balances.Total.Equal(decimal.NewFromInt(3500000)) || balances.Accounts["alice"].Balance != "1000000" {
+		t.Fatalf("unexpected balances: %+v %v", balances, err)
+	}
+}
+
+type credsRecorder struct {
+	mu    sync.Mutex
+	creds []ApiCreds
+}
+
+func (r *credsRecorder) SetApiCreds(creds ApiCreds) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.creds = append(r.creds, creds)
+}
+
+func TestCredentialManagerBootstrapRotateRevoke(t *testing.T) {
+	var mu sync.Mutex
+	var created, revoked []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == EndpointCreateApiKey:
+			key := "key-" + r.Header.Get(signing.HeaderNonce)
+			created = append(created, key)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"apiKey":     key,
+				"secret":     base64.URLEncoding.EncodeToString([]byte(key + "-secret")),
+				"passphrase": key + "-pass",
+			})
+		case r.Method == http.MethodDelete && r.URL.Path == EndpointDeleteApiKey:
+			revoked = append(revoked, r.Header.Get(signing.HeaderApiKey))
+			_, _ = w.Write([]byte(`"OK"`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	key := testSigner(t)
+	client := NewClobClient(WithBaseURL(srv.URL), WithSigner(key))
+	store := NewFileCredentialStore(t.TempDir() + "/creds.json")
+	stream := &credsRecorder{}
+	mgr := NewCredentialManager(client, store, WithRotationOverlap(10*time.Millisecond), WithCredsTargets(stream))
+	ctx := context.Background()
+
+	creds, err := mgr.Bootstrap(ctx)
+	if err != nil || creds.ApiKey != "key-0" || client.ApiCreds().ApiKey != "key-0" {
+		t.Fatalf("bootstrap: creds=%+v err=%v", creds, err)
+	}
+	if err := mgr.Rotate(ctx); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if client.ApiCreds().ApiKey != "key-1" || len(stream.creds) != 2 || stream.creds[1].ApiKey != "key-1" {
+		t.Fatalf("rotated creds not installed: client=%s stream=%+v", client.ApiCreds().ApiKey, stream.creds)
+	}
+	if strings.Join(revoked, ",") != "key-0" {
+		t.Fatalf("old key should be revoked with its own creds: %v", revoked)
+	}
+	stored, err := store.Load(ctx)
+	if err != nil || stored.Creds.ApiKey != "key-1" || stored.Nonce != 1 || stored.Previous != nil {
+		t.Fatalf("unexpected stored creds: %+v %v", stored, err)
+	}
+
+	// A restart picks the rotated key up from the store without L1 calls.
+	restarted := NewClobClient(WithBaseURL(srv.URL), WithSigner(key))
+	if creds, err := NewCredentialManager(restarted, store).Bootstrap(ctx); err != nil || creds.ApiKey != "key-1" {
+		t.Fatalf("restart bootstrap: %+v %v", creds, err)
+	}
+	if strings.Join(created, ",") != "key-0,key-1" {
+		t.Fatalf("unexpected key creations: %v", created)
+	}
+
+	// Run ends with the context's error, like the other Run loops.
+	runCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := NewCredentialManager(restarted, store).Run(runCtx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled from Run, got %v", err)
+	}
+}
+
+func TestFunderDerivationAndValidation(t *testing.T) {
+	key := testSigner(t)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	proxy, err := DeriveProxyWallet(signer, PolygonChainID)
+	if err != nil {
+		t.Fatalf("derive proxy: %v", err)
+	}
+	safe, err := DeriveSafeWallet(signer, PolygonChainID)
+	if err != nil {
+		t.Fatalf("derive safe: %v", err)
+	}
+	if proxy == safe || proxy == signer {
+		t.Fatalf("derived wallets should be distinct: proxy=%s safe=%s", proxy.Hex(), safe.Hex())
+	}
+	if again, _ := DeriveSafeWallet(signer, PolygonChainID); again != safe {
+		t.Fatalf("derivation not deterministic")
+	}
+	if _, err := DeriveProxyWallet(signer, AmoyChainID); err == nil {
+		t.Fatalf("amoy has no proxy factory")
+	}
+
+	// Fixed vectors, recomputed from the raw CREATE2 preimage
+	// 0xff ++ factory ++ salt ++ initCodeHash so a change to a factory, init
+	// code hash or salt encoding cannot pass unnoticed.
+	eoa := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	create2 := func(factory string, salt []byte, initCodeHash common.Hash) common.Address {
+		preimage := append([]byte{0xff}, common.HexToAddress(factory).Bytes()...)
+		preimage = append(preimage, crypto.Keccak256(salt)...)
+		preimage = append(preimage, initCodeHash.Bytes()...)
+		return common.BytesToAddress(crypto.Keccak256(preimage)[12:])
+	}
+	for _, v := range []struct {
+		name   string
+		derive func(common.Address, int) (common.Address, error)
+		raw    common.Address
+		want   string
+	}{
+		{"proxy", DeriveProxyWallet, create2("0xaB45c5A4B0c941a2F231C04C3f49182e1A254052", eoa.Bytes(), ProxyWalletInitCodeHash), "0x365f0CA36Ae1f641E02fE3B7743673da42A13A70"},
+		{"safe", DeriveSafeWallet, create2("0xaacFeEa03eb1561C4e67d661e40682Bd20E3541b", common.LeftPadBytes(eoa.Bytes(), 32), SafeInitCodeHash), "0xd93B25cb943D14d0d34FBaF01Fc93a0f8b5F6E47"},
+	} {
+		got, err := v.derive(eoa, PolygonChainID)
+		if err != nil || got.Hex() != v.want || v.raw.Hex() != v.want {
+			t.Fatalf("%s wallet of %s: got %s (raw %s), want %s: %v", v.name, eoa.Hex(), got.Hex(), v.raw.Hex(), v.want, err)
+		}
+	}
+	if amoySafe, err := DeriveSafeWallet(signer, AmoyChainID); err != nil || amoySafe != safe {
+		t.Fatalf("safe factory is shared with amoy: %s %v", amoySafe.Hex(), err)
+	}
+
+	derived := NewClobClient(WithSigner(key), WithSignatureType(PolyGnosisSafe))
+	if err := derived.ValidateFunder(); err != nil || *derived.funder != safe {
+		t.Fatalf("funder should be derived for safe clients: %v", err)
+	}
+
+	wrong := common.HexToAddress("0x00000000000000000000000000000000000000F0").Hex()
+	var vErr *ValidationError
+	for name, c := range map[string]*ClobClient{
+		"proxy": NewClobClient(WithSigner(key), WithSignatureType(PolyProxy), WithFunderAddress(wrong)),
+		"eoa":   NewClobClient(WithSigner(key), WithFunderAddress(wrong)),
+	} {
+		if err := c.ValidateFunder(); !errors.As(err, &vErr) || vErr.Field != "funder" {
+			t.Fatalf("%s: mismatched funder accepted: %v", name, err)
+		}
+	}
+	unchecked := NewClobClient(WithSigner(key), WithSignatureType(PolyProxy), WithFunderAddress(wrong), WithFunderValidation(false))
+	if err := unchecked.ValidateFunder(); err != nil {
+		t.Fatalf("validation should be disabled: %v", err)
+	}
+}
+
+func TestAPIErrorClassification(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EndpointPostOrder:
+			w.Header().Set("X-Request-Id", "req-123")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"not enough balance / allowance","orderID":"0xabc"}`))
+		case EndpointCancelAll:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"Too Many Requests"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"Unauthorized/Invalid api key"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithSigner(testSigner(t)),
+		WithCreds(testCreds()),
+		WithHTTPOptions(transport.WithMaxRetries(0)),
+	)
+	ctx := context.Background()
+
+	_, err := client.PostOrder(ctx, SignedOrder{}, GTC, false)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("expected insufficient balance APIError, got %v", err)
+	}
+	if apiErr.RequestID != "req-123" || apiErr.Kind != ErrorKindInsufficientBalance || IsRetryable(err) {
+		t.Fatalf("unexpected error %+v", apiErr)
+	}
+	if apiErr.Message != "not enough balance / allowance" || string(apiErr.Fields["orderID"]) != `"0xabc"` {
+		t.Fatalf("unexpected body parse %+v", apiErr)
+	}
+
+	err = client.CancelAll(ctx)
+	if !errors.Is(err, ErrRateLimited) || !IsRetryable(err) {
+		t.Fatalf("expected retryable rate limit, got %v", err)
+	}
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 2*time.Second {
+		t.Fatalf("retry after %s", apiErr.RetryAfter)
+	}
+
+	if _, err := client.GetClosedOnlyMode(ctx); !errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+}
+
+func TestPostOrderReconcilesAmbiguousFailures(t *testing.T) {
+	var mu sync.Mutex
+	var posts, lookups int
+	var bodies []string
+	var dropPost, knownOrder bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == EndpointNegRisk:
+			_, _ = w.Write([]byte(`{"neg_risk":false}`))
+		case r.URL.Path == EndpointPostOrder:
+			posts++
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			switch {
+			case dropPost:
+				// The order is accepted but the connection drops before
+				// the response, as with a client timeout.
+				knownOrder = true
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				_ = conn.Close()
+			case posts == 1:
+				w.WriteHeader(http.StatusBadGateway)
+			default:
+				_, _ = w.Write([]byte(`{"success":true,"orderID":"0xresent","status":"live"}`))
+			}
+		case strings.HasPrefix(r.URL.Path, EndpointOrder):
+			lookups++
+			if !knownOrder {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"order not found"}`))
+				return
+			}
+			id := strings.TrimPrefix(r.URL.Path, EndpointOrder)
+			_, _ = w.Write([]byte(`{"id":"` + id + `","status":"LIVE"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithSigner(testSigner(t)),
+		WithCreds(testCreds()),
+		WithHTTPOptions(transport.WithBaseDelay(time.Millisecond)),
+	)
+	ctx := context.Background()
+	order := sampleSignedOrder()
+
+	// A 502 is ambiguous: the order is looked up, not found, and resent as is.
+	resp, err := client.PostOrder(ctx, order, GTC, false)
+	if err != nil {
+		t.Fatalf("post order: %v", err)
+	}
+	if resp.OrderID != "0xresent" || posts != 2 || lookups != 1 {
+		t.Fatalf("unexpected resp=%+v posts=%d lookups=%d", resp, posts, lookups)
+	}
+	if bodies[0] != bodies[1] {
+		t.Fatalf("resend changed the order:\n%s\n%s", bodies[0], bodies[1])
+	}
+
+	// A dropped connection after the order landed is resolved by the lookup
+	// alone.
+	posts, lookups, dropPost = 0, 0, true
+	resp, err = client.PostOrder(ctx, order, GTC, false)
+	if err != nil {
+		t.Fatalf("post order after drop: %v", err)
+	}
+	hash, err := client.OrderHash(ctx, order)
+	if err != nil {
+		t.Fatalf("order hash: %v", err)
+	}
+	if posts != 1 || lookups != 1 || resp.OrderID != hash || !resp.Success {
+		t.Fatalf("unexpected resp=%+v posts=%d lookups=%d", resp, posts, lookups)
+	}
+
+	// Without resends an unknown order surfaces ErrOutcomeUnknown.
+	noResend := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithSigner(testSigner(t)),
+		WithCreds(testCreds()),
+		WithOrderResends(0),
+	)
+	posts, lookups, dropPost, knownOrder = 0, 0, false, false
+	if _, err := noResend.PostOrder(ctx, order, GTC, false); !errors.Is(err, ErrOutcomeUnknown) || !errors.Is(err, ErrServer) {
+		t.Fatalf("expected unknown outcome, got %v", err)
+	}
+	if posts != 1 || lookups != 1 {
+		t.Fatalf("unexpected posts=%d lookups=%d", posts, lookups)
+	}
+}
+
+func TestReconcileKeepsSettledOrdersAndOutlivesCaller(t *testing.T) {
+	var mu sync.Mutex
+	known := map[string]bool{}
+	var batchPosts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == EndpointNegRisk:
+			_, _ = w.Write([]byte(`{"neg_risk":false}`))
+		case r.URL.Path == EndpointPostOrders:
+			mu.Lock()
+			batchPosts++
+			first := batchPosts == 1
+			mu.Unlock()
+			if first {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			// The resend hangs past the attempt timeout.
+			select {
+			case <-r.Context().Done():
+			case <-time.After(400 * time.Millisecond):
+			}
+		case r.URL.Path == EndpointPostOrder:
+			// The order lands, but only after the caller's deadline.
+			time.Sleep(100 * time.Millisecond)
+			mu.Lock()
+			known["single"] = true
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"success":true,"orderID":"0xlate","status":"live"}`))
+		case strings.HasPrefix(r.URL.Path, EndpointOrder):
+			id := strings.TrimPrefix(r.URL.Path, EndpointOrder)
+			mu.Lock()
+			ok := known[id] || known["single"]
+			mu.Unlock()
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"order not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"` + id + `","status":"LIVE"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithSigner(testSigner(t)),
+		WithCreds(testCreds()),
+		WithHTTPOptions(transport.WithBaseDelay(time.Millisecond), transport.WithAttemptTimeout(200*time.Millisecond)),
+	)
+	ctx := context.Background()
+
+	// The first order rests on the book after the ambiguous post; the second
+	// is unknown and its resend times out. The first must stay accepted.
+	resting, lost := sampleSignedOrder(), sampleSignedOrder()
+	lost.Salt = "2"
+	hash, err := client.OrderHash(ctx, resting)
+	if err != nil {
+		t.Fatalf("order hash: %v", err)
+	}
+	known[hash] = true
+	result, err := client.PostOrders(ctx, []PostOrdersArgs{{Order: resting, OrderType: GTC}, {Order: lost, OrderType: GTC}}, false, false)
+	if !errors.Is(err, ErrOutcomeUnknown) || batchPosts != 2 {
+		t.Fatalf("expected one resend and an unknown outcome, got %d batch posts, err %v", batchPosts, err)
+	}
+	if o := result.Outcomes[0]; o.Err != nil || o.OrderID != hash || o.Status != OrderStatusLive {
+		t.Fatalf("settled order lost: %+v", o)
+	}
+	if o := result.Outcomes[1]; !errors.Is(o.Err, ErrOutcomeUnknown) {
+		t.Fatalf("expected unknown outcome for the unsettled order, got %+v", o)
+	}
+
+	// A post cut short by the caller's deadline is still reconciled.
+	deadline, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	resp, err := client.PostOrder(deadline, sampleSignedOrder(), GTC, false)
+	if err != nil {
+		t.Fatalf("post order after caller deadline: %v", err)
+	}
+	if !resp.Success || resp.OrderID != hash {
+		t.Fatalf("unexpected resp %+v", resp)
+	}
+}
+
+func TestReconcileBatchTreatsDuplicateResendAsAccepted(t *testing.T) {
+	var mu sync.Mutex
+	var batchPosts int
+	var resend string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == EndpointNegRisk:
+			_, _ = w.Write([]byte(`{"neg_risk":false}`))
+		case r.URL.Path == EndpointPostOrders:
+			batchPosts++
+			if batchPosts%2 == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			// The first posts landed after all, between lookup and resend.
+			if resend == "request" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"order already exists"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"success":false,"errorMsg":"duplicate order"},{"success":true,"orderID":"0xnew","status":"live"}]`))
+		case strings.HasPrefix(r.URL.Path, EndpointOrder):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"order not found"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClobClient(
+		WithBaseURL(srv.URL),
+		WithSigner(testSigner(t)),
+		WithCreds(testCreds()),
+		WithHTTPOptions(transport.WithBaseDelay(time.Millisecond)),
+	)
+	ctx := context.Background()
+	first, second := sampleSignedOrder(), sampleSignedOrder()
+	second.Salt = "2"
+	hashes := make([]string, 2)
+	for i, o := range []SignedOrder{first, second} {
+		h, err := client.OrderHash(ctx, o)
+		if err != nil {
+			t.Fatalf("order hash: %v", err)
+		}
+		hashes[i] = h
+	}
+	args := []PostOrdersArgs{{Order: first, OrderType: GTC}, {Order: second, OrderType: GTC}}
+
+	// A duplicate rejection of one order in the resend response.
+	result, err := client.PostOrders(ctx, args, false, false)
+	if err != nil {
+		t.Fatalf("post orders: %v", err)
+	}
+	if o := result.Outcomes[0]; o.Err != nil || o.OrderID != hashes[0] {
+		t.Fatalf("duplicate resend reported as failed: %+v", o)
+	}
+	if o := result.Outcomes[1]; o.Err != nil || o.OrderID != "0xnew" {
+		t.Fatalf("unexpected second outcome: %+v", o)
+	}
+
+	// A duplicate rejection of the whole resend request.
+	mu.Lock()
+	resend = "request"
+	mu.Unlock()
+	result, err = client.PostOrders(ctx, args, false, false)
+	if err != nil {
+		t.Fatalf("post orders: %v", err)
+	}
+	for i, o := range result.Outcomes {
+		if o.Err != nil || o.OrderID != hashes[i] {
+			t.Fatalf("duplicate resend reported as failed: %+v", o)
+		}
+	}
+}
+
+func TestL2SignatureCoversBytesOnTheWire(t *testing.T) {
+	creds := testCreds()
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		want, err := signing.BuildHMACSignature(creds.ApiSecret, r.Header.Get(signing.HeaderTimestamp), r.Method, r.URL.Path, string(body))
+		if err != nil || r.Header.Get(signing.HeaderSignature) != want {
+			t.Errorf("signature does not match body %q", body)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// An encoder whose output differs from json.Marshal: the signature must
+	// cover its bytes, not a re-encoding.
+	indent := func(v any) ([]byte, error) { return json.MarshalIndent(v, "", "  ") }
+	client := NewClobClient(WithBaseURL(srv.URL), WithSigner(testSigner(t)), WithCreds(creds), WithEncoder(indent))
+	ctx := context.Background()
+
+	if err := client.PostHeartbeat(ctx, "hb-1"); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	raw := RawBody(`{"heartbeat_id":  "hb-2"}`)
+	if _, err := client.DoAuthenticated(ctx, http.MethodPost, EndpointHeartbeat, nil, raw); err != nil {
+		t.Fatalf("raw request: %v", err)
+	}
+
+	if len(bodies) != 2 || !strings.Contains(bodies[0], "\n  ") || bodies[1] != string(raw) {
+		t.Fatalf("unexpected bodies on the wire: %q", bodies)
+	}
+}
+
+func TestRecordAndReplayBalanceAllowance(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"balance":"7","allowance":"9"}`))
+	}))
+	defer srv.Close()
+
+	var buf strings.Builder
+	rec := replay.NewRecorderWriter(&buf)
+	key := testSigner(t)
+	params := BalanceAllowanceParams{AssetType: string(AssetTypeCollateral)}
+
+	live := NewClobClient(WithBaseURL(srv.URL), WithSigner(key), WithCreds(testCreds()), WithRecorder(rec))
+	if _, err := live.GetBalanceAllowance(context.Background(), params); err != nil {
+		t.Fatalf("live request: %v", err)
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatalf("recorder: %v", err)
+	}
+	if strings.Contains(buf.String(), testCreds().ApiPassphrase) {
+		t.Fatalf("recording leaked passphrase: %s", buf.String())
+	}
+
+	rp, err := replay.LoadReader(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	// Replay against an unreachable host with different credentials, so every
+	// HMAC header differs from the recorded request.
+	otherCreds := testCreds()
+	otherCreds.ApiKey = "other-key"
+	offline := NewClobClient(WithBaseURL("http://127.0.0.1:1"), WithSigner(key), WithCreds(otherCreds), WithReplay(rp))
+	got, err := offline.GetBalanceAllowance(context.Background(), params)
+	if err != nil {
+		t.Fatalf("replayed request: %v", err)
+	}
+	if got.Balance != "7" || got.Allowance != "9" {
+		t.Fatalf("unexpected replayed response: %+v", got)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single live call, got %d", calls)
+	}
+
+	if _, err := offline.GetOrderBook(context.Background(), "1"); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Fatalf("expected unmatched request error, got %v", err)
+	}
+}
+
+func TestRecordAndReplayOrderSession(t *testing.T) {
+	liveCreds := testCreds()
+	liveCreds.ApiKey, liveCreds.ApiPassphrase = "live-key", "live-passphrase"
+	var posts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EndpointCreateApiKey:
+			_ = json.NewEncoder(w).Encode(liveCreds)
+		case EndpointTickSize:
+			_, _ = w.Write([]byte(`{"minimum_tick_size":0.01}`))
+		case EndpointNegRisk:
+			_, _ = w.Write([]byte(`{"neg_risk":false}`))
+		case EndpointFeeRate:
+			_, _ = w.Write([]byte(`{"base_fee":0}`))
+		case EndpointPostOrder:
+			posts++
+			_, _ = w.Write([]byte(`{"success":true,"orderID":"0xrecorded","status":"live"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	key := testSigner(t)
+	args := OrderArgs{TokenID: "1", Price: decimal.RequireFromString("0.5"), Size: decimal.RequireFromString("10"), Side: Buy}
+	session := func(c *ClobClient) (*OrderResponse, error) {
+		ctx := context.Background()
+		creds, err := c.CreateApiKey(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		c.SetApiCreds(*creds)
+		order, err := c.CreateOrder(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return c.PostOrder(ctx, *order, GTC, false)
+	}
+
+	var buf strings.Builder
+	rec := replay.NewRecorderWriter(&buf)
+	if _, err := session(NewClobClient(WithBaseURL(srv.URL), WithSigner(key), WithRecorder(rec))); err != nil {
+		t.Fatalf("live session: %v", err)
+	}
+	for _, secret := range []string{liveCreds.ApiKey, liveCreds.ApiSecret, liveCreds.ApiPassphrase} {
+		if strings.Contains(buf.String(), secret) {
+			t.Fatalf("recording leaked %q: %s", secret, buf.String())
+		}
+	}
+
+	// The replayed run signs a new order with a fresh salt and signature.
+	rp, err := replay.LoadReader(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	resp, err := session(NewClobClient(WithBaseURL("http://127.0.0.1:1"), WithSigner(key), WithReplay(rp)))
+	if err != nil {
+		t.Fatalf("replayed session: %v", err)
+	}
+	if resp.OrderID != "0xrecorded" || posts != 1 {
+		t.Fatalf("unexpected replayed resp=%+v posts=%d", resp, posts)
+	}
+}
+
+func TestMarketCacheIndexesAndEvents(t *testing.T) {
+	m1 := `{"condition_id":"c1","market_slug":"will-it-rain","tags":["weather"],"accepting_orders":true,"minimum_tick_size":0.01,"neg_risk":true,` +
+		`"tokens":[{"token_id":"t1","outcome":"Yes"},{"token_id":"t2","outcome":"No"}]}`
+	m2 := `{"condition_id":"c2","market_slug":"will-it-snow","tags":["weather","winter"],"accepting_orders":true,"minimum_tick_size":0.01,` +
+		`"tokens":[{"token_id":"t3","outcome":"Yes"},{"token_id":"t4","outcome":"No"}]}`
+	m3 := `{"condition_id":"c3","market_slug":"new","accepting_orders":true,"minimum_tick_size":0.001,"tokens":[{"token_id":"t5"}]}`
+
+	var mu sync.Mutex
+	pages := map[string]string{
+		"":     `{"data":[` + m1 + `],"next_cursor":"MQ=="}`,
+		"MQ==": `{"data":[` + m2 + `],"next_cursor":"LTE="}`,
+	}
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != EndpointMarkets {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		cursor := r.URL.Query().Get("next_cursor")
+		cursors = append(cursors, cursor)
+		_, _ = w.Write([]byte(pages[cursor]))
+	}))
+	defer srv.Close()
+
+	var events []MarketEvent
+	client := NewClobClient(WithBaseURL(srv.URL))
+	cache := NewMarketCache(client, WithMarketEventHandler(func(ev MarketEvent) { events = append(events, ev) }))
+
+	ctx := context.Background()
+	if err := cache.Load(ctx); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cache.Len() != 2 || len(events) != 0 {
+		t.Fatalf("after load: len=%d events=%d", cache.Len(), len(events))
+	}
+	if m, ok := cache.MarketByToken("t4"); !ok || m.ConditionID != "c2" {
+		t.Fatalf("token index: %+v %v", m, ok)
+	}
+	if m, ok := cache.MarketBySlug("will-it-rain"); !ok || m.ConditionID != "c1" {
+		t.Fatalf("slug index: %+v %v", m, ok)
+	}
+	if got := cache.MarketsByTag("weather"); len(got) != 2 || got[0].ConditionID != "c1" {
+		t.Fatalf("tag index: %+v", got)
+	}
+	// Client metadata caches are seeded, so no tick size or neg-risk request.
+	if ts, err := client.GetTickSize(ctx, "t1"); err != nil || ts != "0.01" {
+		t.Fatalf("tick size: %q %v", ts, err)
+	}
+	if nr, err := client.GetNegRisk(ctx, "t2"); err != nil || !nr {
+		t.Fatalf("neg risk: %v %v", nr, err)
+	}
+
+	// A new listing on the last page is found without re-reading page one.
+	mu.Lock()
+	pages["MQ=="] = `{"data":[` + m2 + `,` + m3 + `],"next_cursor":"LTE="}`
+	cursors = nil
+	mu.Unlock()
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if len(cursors) != 1 || cursors[0] != "MQ==" {
+		t.Fatalf("refresh re-read pages: %v", cursors)
+	}
+	if len(events) != 1 || events[0].Type != MarketOpened || events[0].Market.ConditionID != "c3" || events[0].Previous != nil {
+		t.Fatalf("refresh events: %+v", events)
+	}
+
+	// Closing and resolving c1, and a stopped c2, are reported on reload.
+	events = nil
+	mu.Lock()
+	pages[""] = `{"data":[` + strings.Replace(strings.Replace(m1, `"accepting_orders":true`, `"accepting_orders":false,"closed":true`, 1),
+		`{"token_id":"t1","outcome":"Yes"}`, `{"token_id":"t1","outcome":"Yes","winner":true}`, 1) + `],"next_cursor":"MQ=="}`
+	pages["MQ=="] = `{"data":[` + strings.Replace(m2, `"accepting_orders":true`, `"accepting_orders":false`, 1) + `,` + m3 + `],"next_cursor":"LTE="}`
+	mu.Unlock()
+	if err := cache.Load(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	var got []string
+	for _, ev := range events {
+		got = append(got, string(ev.Type)+":"+ev.Market.ConditionID+":"+ev.TokenID)
+	}
+	if want := "closed:c1:,resolved:c1:t1,stopped_accepting:c2:"; strings.Join(got, ",") != want {
+		t.Fatalf("reload events: %v", got)
+	}
+
+	// Tick size pushes from the WebSocket update the market and the client.
+	events = nil
+	if err := cache.ApplyTickSizeChange("t3", "0.001"); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != MarketTickSizeChanged || events[0].TokenID != "t3" {
+		t.Fatalf("tick events: %+v", events)
+	}
+	if ts, _ := client.GetTickSize(ctx, "t3"); ts != "0.001" {
+		t.Fatalf("client tick size not updated: %q", ts)
+	}
+	if m, _ := cache.Market("c2"); !m.MinimumTickSize.Equal(decimal.RequireFromString("0.001")) {
+		t.Fatalf("cached tick size: %s", m.MinimumTickSize)
+	}
+
+	// A market no longer listed is evicted from every index on reload.
+	events = nil
+	mu.Lock()
+	pages[""] = `{"data":[],"next_cursor":"MQ=="}`
+	mu.Unlock()
+	if err := cache.Load(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	var removed []string
+	for _, ev := range events {
+		if ev.Type == MarketRemoved {
+			removed = append(removed, ev.Market.ConditionID)
+		}
+	}
+	if len(removed) != 1 || removed[0] != "c1" {
+		t.Fatalf("removal events: %v", removed)
+	}
+	if _, ok := cache.MarketByToken("t1"); ok {
+		t.Fatalf("removed market still indexed by token")
+	}
+	if _, ok := cache.MarketBySlug("will-it-rain"); ok {
+		t.Fatalf("removed market still indexed by slug")
+	}
+	if got := cache.MarketsByTag("weather"); len(got) != 1 || got[0].ConditionID != "c2" || cache.Len() != 2 {
+		t.Fatalf("tag index after removal: %+v (len %d)", got, cache.Len())
+	}
+}
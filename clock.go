@@ -0,0 +1,201 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lubluniky/clob-client-go/observe"
+)
+
+// defaultClockSamples is the number of ServerTime round trips taken per sync.
+// The sample with the lowest round-trip time gives the tightest offset bound.
+const defaultClockSamples = 3
+
+// ClockStats is a snapshot of the measured drift between the local clock and
+// the CLOB server.
+type ClockStats struct {
+	// Offset is server time minus local time. Positive means the local clock
+	// is behind the server.
+	Offset time.Duration
+	// RTT is the round-trip time of the sample the offset was derived from.
+	RTT time.Duration
+	// LastSync is the local time of the last successful sync (zero if never).
+	LastSync time.Time
+	// Syncs counts successful syncs.
+	Syncs uint64
+	// LastError is the error from the most recent failed sync, if any.
+	LastError error
+}
+
+// ClockSync estimates the offset between the local clock and the server clock
+// by sampling ServerTime. When attached to a ClobClient via WithClockSync, L1
+// and L2 auth timestamps and GTD expirations use server-corrected time.
+//
+// Because the server reports whole seconds, the offset is only meaningful at
+// second granularity, which matches the resolution of auth timestamps.
+type ClockSync struct {
+	client   *ClobClient
+	interval time.Duration
+	samples  int
+
+	mu    sync.RWMutex
+	stats ClockStats
+
+	syncing     atomic.Bool
+	lastAttempt atomic.Int64 // unix nanos of the last background sync
+}
+
+// WithClockSync enables server clock synchronisation. The offset is refreshed
+// in the background whenever it is older than interval (a non-positive
+// interval disables automatic refresh). Call Clock().Sync(ctx) after
+// construction to block until the first measurement is available; until then
+// the local clock is used unchanged.
+func WithClockSync(interval time.Duration) ClientOption {
+	return func(c *ClobClient) {
+		c.clock = &ClockSync{
+			client:   c,
+			interval: interval,
+			samples:  defaultClockSamples,
+		}
+	}
+}
+
+// Clock returns the client's clock-sync component, or nil when WithClockSync
+// was not configured.
+func (c *ClobClient) Clock() *ClockSync {
+	return c.clock
+}
+
+// Now returns the current time, corrected for server drift when clock sync is
+// enabled.
+func (c *ClobClient) Now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// GTDExpiration returns a unix timestamp ttl from now (server-corrected when
+// clock sync is enabled), suitable for OrderArgs.Expiration on GTD orders.
+// CreateOrder applies it itself when OrderArgs.TTL is set.
+func (c *ClobClient) GTDExpiration(ttl time.Duration) int {
+	return int(c.Now().Add(ttl).Unix())
+}
+
+// Now returns local time adjusted by the measured offset. A stale measurement
+// triggers a background refresh.
+func (s *ClockSync) Now() time.Time {
+	s.mu.RLock()
+	offset := s.stats.Offset
+	lastSync := s.stats.LastSync
+	s.mu.RUnlock()
+
+	if s.interval > 0 && time.Since(lastSync) > s.interval {
+		s.refreshAsync()
+	}
+	return time.Now().Add(offset)
+}
+
+// Offset returns the current estimate of server time minus local time.
+func (s *ClockSync) Offset() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stats.Offset
+}
+
+// Stats returns a snapshot of the clock drift metrics.
+func (s *ClockSync) Stats() ClockStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stats
+}
+
+// Sync samples ServerTime and updates the offset estimate. The sample with the
+// lowest round-trip time is used, assuming symmetric network delay. Each sync
+// is reported to the client's observe.Hooks.OnClockSync.
+func (s *ClockSync) Sync(ctx context.Context) error {
+	var (
+		best    time.Duration
+		bestRTT time.Duration = -1
+		lastErr error
+	)
+	for i := 0; i < s.samples; i++ {
+		sent := time.Now()
+		ts, err := s.client.ServerTime(ctx)
+		received := time.Now()
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		rtt := received.Sub(sent)
+		midpoint := sent.Add(rtt / 2)
+		offset := time.Unix(ts, 0).Sub(midpoint)
+		// The server truncates to whole seconds; centre the estimate within
+		// that second so the error is bounded by +/-0.5s plus rtt/2.
+		offset += 500 * time.Millisecond
+		if bestRTT < 0 || rtt < bestRTT {
+			best, bestRTT = offset, rtt
+		}
+	}
+
+	ev := observe.ClockSync{Err: lastErr}
+	s.mu.Lock()
+	if bestRTT >= 0 {
+		ev = observe.ClockSync{Offset: best.Round(time.Millisecond), RTT: bestRTT}
+		s.stats.Offset = ev.Offset
+		s.stats.RTT = ev.RTT
+		s.stats.LastSync = time.Now()
+		s.stats.Syncs++
+	}
+	s.stats.LastError = ev.Err
+	s.mu.Unlock()
+
+	if h := s.client.hooks; h != nil {
+		h.OnClockSync(ev)
+	}
+	return ev.Err
+}
+
+// Run re-syncs on every interval until ctx is cancelled. It is an alternative
+// to the lazy refresh performed by Now for callers that want a fixed cadence.
+func (s *ClockSync) Run(ctx context.Context) {
+	interval := s.interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	_ = s.Sync(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.Sync(ctx)
+		}
+	}
+}
+
+// refreshAsync starts a background sync unless one is already running.
+func (s *ClockSync) refreshAsync() {
+	// Don't hammer the server while it is failing: attempt at most once per
+	// interval regardless of outcome.
+	if time.Since(time.Unix(0, s.lastAttempt.Load())) < s.interval {
+		return
+	}
+	if !s.syncing.CompareAndSwap(false, true) {
+		return
+	}
+	s.lastAttempt.Store(time.Now().UnixNano())
+	go func() {
+		defer s.syncing.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = s.Sync(ctx)
+	}()
+}
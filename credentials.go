@@ -0,0 +1,369 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Default CredentialManager timings.
+const (
+	DefaultRotationInterval = 7 * 24 * time.Hour
+	DefaultRotationOverlap  = time.Minute
+	// rotationRetryDelay is how long Run waits after a failed rotation or
+	// revocation before trying again.
+	rotationRetryDelay = time.Minute
+)
+
+// StoredCreds is what a CredentialStore persists: the current API key, the
+// L1 nonce it was created with, and a previous key still awaiting
+// revocation after a rotation.
+type StoredCreds struct {
+	Creds     ApiCreds  `json:"creds"`
+	Nonce     int       `json:"nonce"`
+	CreatedAt time.Time `json:"created_at"`
+	// Previous is revoked once RevokeAt has passed.
+	Previous *ApiCreds `json:"previous,omitempty"`
+	RevokeAt time.Time `json:"revoke_at,omitzero"`
+}
+
+// CredentialStore persists API credentials between runs. Load returns nil and
+// no error when nothing is stored. Implementations must be safe for
+// concurrent use.
+type CredentialStore interface {
+	Load(ctx context.Context) (*StoredCreds, error)
+	Save(ctx context.Context, creds StoredCreds) error
+}
+
+// MemoryCredentialStore keeps credentials in memory, for tests and
+// short-lived processes.
+type MemoryCredentialStore struct {
+	mu    sync.Mutex
+	creds *StoredCreds
+}
+
+// NewMemoryCredentialStore returns an empty in-memory store.
+func NewMemoryCredentialStore() *MemoryCredentialStore {
+	return &MemoryCredentialStore{}
+}
+
+func (s *MemoryCredentialStore) Load(context.Context) (*StoredCreds, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.creds == nil {
+		return nil, nil
+	}
+	creds := *s.creds
+	return &creds, nil
+}
+
+func (s *MemoryCredentialStore) Save(_ context.Context, creds StoredCreds) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creds = &creds
+	return nil
+}
+
+// FileCredentialStore keeps credentials in a JSON file readable only by the
+// owner. Saves replace the file atomically.
+type FileCredentialStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCredentialStore returns a store backed by the file at path.
+func NewFileCredentialStore(path string) *FileCredentialStore {
+	return &FileCredentialStore{path: path}
+}
+
+func (s *FileCredentialStore) Load(context.Context) (*StoredCreds, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("polymarket: reading credentials: %w", err)
+	}
+	var creds StoredCreds
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("polymarket: parsing credentials file %s: %w", s.path, err)
+	}
+	return &creds, nil
+}
+
+func (s *FileCredentialStore) Save(_ context.Context, creds StoredCreds) error {
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return fmt.Errorf("polymarket: marshalling credentials: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("polymarket: writing credentials: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("polymarket: writing credentials: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("polymarket: writing credentials: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("polymarket: writing credentials: %w", err)
+	}
+	return nil
+}
+
+// CredsSetter receives rotated credentials. *ClobClient and *ws.Client
+// implement it.
+type CredsSetter interface {
+	SetApiCreds(creds ApiCreds)
+}
+
+// CredentialManagerOption configures a CredentialManager.
+type CredentialManagerOption func(*CredentialManager)
+
+// WithRotationInterval sets how old a key gets before Run rotates it.
+// Default DefaultRotationInterval; a non-positive interval disables rotation.
+func WithRotationInterval(d time.Duration) CredentialManagerOption {
+	return func(m *CredentialManager) { m.interval = d }
+}
+
+// WithRotationOverlap sets how long the previous key stays valid after a
+// rotation, so requests signed just before the switch still authenticate.
+// Default DefaultRotationOverlap.
+func WithRotationOverlap(d time.Duration) CredentialManagerOption {
+	return func(m *CredentialManager) { m.overlap = max(d, 0) }
+}
+
+// WithCredsTargets adds receivers of rotated credentials besides the client,
+// such as a ws.Client with user subscriptions. A ws.Client built with
+// ws.WithCredsFrom(client) follows the client already and need not be added.
+func WithCredsTargets(targets ...CredsSetter) CredentialManagerOption {
+	return func(m *CredentialManager) { m.targets = append(m.targets, targets...) }
+}
+
+// WithRotationErrorHandler receives errors from Run's background rotations
+// and revocations. Run retries them after a minute.
+func WithRotationErrorHandler(fn func(error)) CredentialManagerOption {
+	return func(m *CredentialManager) { m.onError = fn }
+}
+
+// CredentialManager owns the API key lifecycle of a client: it bootstraps
+// credentials from L1 auth or a CredentialStore, rotates the key on a
+// schedule, and revokes the old key once the overlap window has passed.
+type CredentialManager struct {
+	client   *ClobClient
+	store    CredentialStore
+	interval time.Duration
+	overlap  time.Duration
+	targets  []CredsSetter
+	onError  func(error)
+
+	rotating sync.Mutex // serializes Bootstrap, Rotate and revocations
+	mu       sync.Mutex
+	current  *StoredCreds
+}
+
+// NewCredentialManager returns a manager for c's API key. c needs a signer
+// for the L1 calls that create keys.
+func NewCredentialManager(c *ClobClient, store CredentialStore, opts ...CredentialManagerOption) *CredentialManager {
+	m := &CredentialManager{
+		client:   c,
+		store:    store,
+		interval: DefaultRotationInterval,
+		overlap:  DefaultRotationOverlap,
+		onError:  func(error) {},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Bootstrap installs the stored credentials or, if the store is empty,
+// creates or derives a key with L1 auth and stores it. It returns the
+// credentials now in use.
+func (m *CredentialManager) Bootstrap(ctx context.Context) (*ApiCreds, error) {
+	m.rotating.Lock()
+	defer m.rotating.Unlock()
+
+	stored, err := m.store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		creds, err := m.client.CreateOrDeriveApiKey(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("polymarket: bootstrapping credentials: %w", err)
+		}
+		stored = &StoredCreds{Creds: *creds, CreatedAt: time.Now()}
+		if err := m.store.Save(ctx, *stored); err != nil {
+			return nil, err
+		}
+	}
+	m.install(*stored)
+	creds := stored.Creds
+	return &creds, nil
+}
+
+// Current returns a copy of the managed credentials state, or nil before
+// Bootstrap.
+func (m *CredentialManager) Current() *StoredCreds {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.current == nil {
+		return nil
+	}
+	cur := *m.current
+	return &cur
+}
+
+// install makes stored current and hands its key to the client and every
+// target under one lock, so concurrent rotations cannot interleave.
+func (m *CredentialManager) install(stored StoredCreds) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = &stored
+	m.client.SetApiCreds(stored.Creds)
+	for _, t := range m.targets {
+		t.SetApiCreds(stored.Creds)
+	}
+}
+
+// Rotate creates a new key, stores and installs it, waits out the overlap
+// window and revokes the old key. If ctx ends during the overlap, the old key
+// stays in the store and is revoked by the next Run or Rotate.
+func (m *CredentialManager) Rotate(ctx context.Context) error {
+	m.rotating.Lock()
+	defer m.rotating.Unlock()
+
+	cur := m.Current()
+	if cur == nil {
+		return fmt.Errorf("polymarket: rotating credentials: not bootstrapped")
+	}
+	// A previous rotation's key must go first; the store keeps only one.
+	if err := m.revokeLocked(ctx, cur, true); err != nil {
+		return err
+	}
+	cur = m.Current()
+
+	nonce := cur.Nonce + 1
+	creds, err := m.client.CreateApiKey(ctx, nonce)
+	if err != nil {
+		// An interrupted rotation may already have created this nonce's key.
+		if creds, err = m.client.DeriveApiKey(ctx, nonce); err != nil {
+			return fmt.Errorf("polymarket: rotating credentials: %w", err)
+		}
+	}
+	old := cur.Creds
+	next := StoredCreds{
+		Creds:     *creds,
+		Nonce:     nonce,
+		CreatedAt: time.Now(),
+		Previous:  &old,
+		RevokeAt:  time.Now().Add(m.overlap),
+	}
+	if err := m.store.Save(ctx, next); err != nil {
+		return err
+	}
+	m.install(next)
+
+	if err := sleepCtx(ctx, m.overlap); err != nil {
+		return nil // rotated; revocation is left to the next run
+	}
+	return m.revokeLocked(ctx, &next, true)
+}
+
+// revokeLocked revokes cur's previous key if there is one and, unless force
+// is set, its overlap window has passed.
+func (m *CredentialManager) revokeLocked(ctx context.Context, cur *StoredCreds, force bool) error {
+	if cur.Previous == nil || (!force && time.Now().Before(cur.RevokeAt)) {
+		return nil
+	}
+	_, err := m.client.send(ctx, apiRequest{
+		method: http.MethodDelete,
+		path:   EndpointDeleteApiKey,
+		auth:   authL2,
+		creds:  cur.Previous,
+	})
+	// A key the server no longer accepts is as good as revoked.
+	if err != nil && !errors.Is(err, ErrUnauthorized) && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("polymarket: revoking previous api key: %w", err)
+	}
+	done := *cur
+	done.Previous, done.RevokeAt = nil, time.Time{}
+	if err := m.store.Save(ctx, done); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.current = &done
+	m.mu.Unlock()
+	return nil
+}
+
+// Run bootstraps if needed, then rotates the key whenever it is older than
+// the rotation interval and revokes leftover previous keys, until ctx ends.
+// Failures go to the WithRotationErrorHandler handler and are retried. It
+// returns ctx.Err(), or the bootstrap error.
+func (m *CredentialManager) Run(ctx context.Context) error {
+	if m.Current() == nil {
+		if _, err := m.Bootstrap(ctx); err != nil {
+			return err
+		}
+	}
+	for {
+		wait, err := m.tick(ctx)
+		if err != nil {
+			m.onError(err)
+			wait = rotationRetryDelay
+		}
+		if err := sleepCtx(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// tick does any revocation or rotation that is due and returns how long to
+// wait before the next one.
+func (m *CredentialManager) tick(ctx context.Context) (time.Duration, error) {
+	cur := m.Current()
+	if cur.Previous != nil {
+		if wait := time.Until(cur.RevokeAt); wait > 0 {
+			return wait, nil
+		}
+		m.rotating.Lock()
+		err := m.revokeLocked(ctx, m.Current(), false)
+		m.rotating.Unlock()
+		return 0, err
+	}
+	if m.interval <= 0 {
+		return time.Hour, nil
+	}
+	if wait := time.Until(cur.CreatedAt.Add(m.interval)); wait > 0 {
+		return wait, nil
+	}
+	return 0, m.Rotate(ctx)
+}
+
+// sleepCtx waits for d or until ctx ends, returning ctx's error in that case.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -3,28 +3,68 @@ package client
 import (
 	"errors"
 	"fmt"
+
+	"github.com/lubluniky/clob-client-go/internal/transport"
 )
 
-// APIError represents an error response from the Polymarket API.
-type APIError struct {
-	StatusCode int
-	Method     string
-	Path       string
-	Message    string
-}
+// APIError is an error response from the Polymarket API. The JSON error body
+// is parsed into Message, Code and Fields, and the error is classified by
+// Kind so that errors.Is matches the sentinels below:
+//
+//	if errors.Is(err, polymarket.ErrInsufficientBalance) { ... }
+//
+//	var apiErr *polymarket.APIError
+//	if errors.As(err, &apiErr) {
+//		log.Printf("request %s failed (%s), retry after %s", apiErr.RequestID, apiErr.Kind, apiErr.RetryAfter)
+//	}
+type APIError = transport.APIError
 
-func (e *APIError) Error() string {
-	return fmt.Sprintf("polymarket: %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
-}
+// ErrorKind classifies an APIError by cause.
+type ErrorKind = transport.ErrorKind
+
+// Error kinds.
+const (
+	ErrorKindUnknown             = transport.ErrorKindUnknown
+	ErrorKindAuth                = transport.ErrorKindAuth
+	ErrorKindRateLimit           = transport.ErrorKindRateLimit
+	ErrorKindNotFound            = transport.ErrorKindNotFound
+	ErrorKindInsufficientBalance = transport.ErrorKindInsufficientBalance
+	ErrorKindInvalidTick         = transport.ErrorKindInvalidTick
+	ErrorKindMarketClosed        = transport.ErrorKindMarketClosed
+	ErrorKindPostOnlyCross       = transport.ErrorKindPostOnlyCross
+	ErrorKindDuplicateOrder      = transport.ErrorKindDuplicateOrder
+	ErrorKindInvalidRequest      = transport.ErrorKindInvalidRequest
+	ErrorKindServer              = transport.ErrorKindServer
+)
 
-// Sentinel errors for common HTTP status codes.
+// Sentinel errors matched by *APIError via errors.Is.
 var (
-	ErrUnauthorized = errors.New("polymarket: unauthorized (401)")
-	ErrForbidden    = errors.New("polymarket: forbidden (403)")
-	ErrNotFound     = errors.New("polymarket: not found (404)")
-	ErrRateLimited  = errors.New("polymarket: rate limited (429)")
+	ErrUnauthorized        = transport.ErrUnauthorized
+	ErrForbidden           = transport.ErrForbidden
+	ErrNotFound            = transport.ErrNotFound
+	ErrRateLimited         = transport.ErrRateLimited
+	ErrInsufficientBalance = transport.ErrInsufficientBalance
+	ErrInvalidTickSize     = transport.ErrInvalidTickSize
+	ErrMarketClosed        = transport.ErrMarketClosed
+	ErrPostOnlyWouldCross  = transport.ErrPostOnlyWouldCross
+	ErrDuplicateOrder      = transport.ErrDuplicateOrder
+	ErrServer              = transport.ErrServer
 )
 
+// ErrOutcomeUnknown is matched by errors from requests that may or may not
+// have taken effect, such as an order post that timed out after it was sent.
+// PostOrder and PostOrders reconcile these themselves before returning.
+var ErrOutcomeUnknown = transport.ErrOutcomeUnknown
+
+// ErrCircuitOpen is matched by errors from requests refused, without being
+// sent, because their endpoint group's circuit breaker is open. See
+// WithCircuitBreaker.
+var ErrCircuitOpen = transport.ErrCircuitOpen
+
+// CircuitOpenError is the error returned for a request refused by an open
+// circuit; it matches ErrCircuitOpen.
+type CircuitOpenError = transport.CircuitOpenError
+
 // AuthError indicates an authentication/signing failure.
 type AuthError struct {
 	Message string
@@ -44,11 +84,13 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("polymarket validation: %s: %s", e.Field, e.Message)
 }
 
-// IsRetryable returns true if the error is transient and the request can be retried.
+// IsRetryable returns true if the error is transient and the request can be
+// retried: rate limits, server errors and network failures such as timeouts
+// or reset connections. It does not know whether the request is idempotent.
 func IsRetryable(err error) bool {
 	var apiErr *APIError
 	if errors.As(err, &apiErr) {
-		return apiErr.StatusCode >= 500 || apiErr.StatusCode == 429
+		return apiErr.Retryable()
 	}
-	return false
+	return transport.IsTransient(err)
 }
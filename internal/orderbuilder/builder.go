@@ -125,6 +125,25 @@ func CalculateMarketOrderAmounts(side string, amount, price decimal.Decimal, tic
 
 // SignOrder signs an OrderData using EIP-712 typed data signing for the CTF exchange.
 func SignOrder(key *ecdsa.PrivateKey, chainID int, order OrderData, negRisk bool) (string, error) {
+	hash, err := HashOrder(chainID, order, negRisk)
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		return "", fmt.Errorf("orderbuilder: signing failed: %w", err)
+	}
+
+	// Adjust V: 0/1 -> 27/28
+	sig[64] += 27
+
+	return fmt.Sprintf("0x%x", sig), nil
+}
+
+// HashOrder returns the EIP-712 digest of an order, which the exchange uses as
+// the order ID.
+func HashOrder(chainID int, order OrderData, negRisk bool) (common.Hash, error) {
 	// Select the correct exchange address
 	var exchangeAddr common.Address
 	switch {
@@ -137,7 +156,7 @@ func SignOrder(key *ecdsa.PrivateKey, chainID int, order OrderData, negRisk bool
 	case chainID == 80002 && negRisk:
 		exchangeAddr = AmoyNegRiskExchange
 	default:
-		return "", fmt.Errorf("orderbuilder: unsupported chain ID: %d", chainID)
+		return common.Hash{}, fmt.Errorf("orderbuilder: unsupported chain ID: %d", chainID)
 	}
 
 	// Convert string fields to big.Int strings where the EIP-712 type is uint256.
@@ -145,7 +164,7 @@ func SignOrder(key *ecdsa.PrivateKey, chainID int, order OrderData, negRisk bool
 	// uint256 fields.
 	tokenID := new(big.Int)
 	if _, ok := tokenID.SetString(order.TokenID, 10); !ok {
-		return "", fmt.Errorf("orderbuilder: invalid tokenID: %s", order.TokenID)
+		return common.Hash{}, fmt.Errorf("orderbuilder: invalid tokenID: %s", order.TokenID)
 	}
 
 	typedData := apitypes.TypedData{
@@ -197,30 +216,20 @@ func SignOrder(key *ecdsa.PrivateKey, chainID int, order OrderData, negRisk bool
 	// Hash domain separator
 	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
 	if err != nil {
-		return "", fmt.Errorf("orderbuilder: domain hash failed: %w", err)
+		return common.Hash{}, fmt.Errorf("orderbuilder: domain hash failed: %w", err)
 	}
 
 	// Hash the Order message
 	messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
 	if err != nil {
-		return "", fmt.Errorf("orderbuilder: message hash failed: %w", err)
+		return common.Hash{}, fmt.Errorf("orderbuilder: message hash failed: %w", err)
 	}
 
 	// EIP-712 hash
 	rawData := []byte{0x19, 0x01}
 	rawData = append(rawData, domainSeparator...)
 	rawData = append(rawData, messageHash...)
-	hash := crypto.Keccak256Hash(rawData)
-
-	sig, err := crypto.Sign(hash.Bytes(), key)
-	if err != nil {
-		return "", fmt.Errorf("orderbuilder: signing failed: %w", err)
-	}
-
-	// Adjust V: 0/1 -> 27/28
-	sig[64] += 27
-
-	return fmt.Sprintf("0x%x", sig), nil
+	return crypto.Keccak256Hash(rawData), nil
 }
 
 // ValidatePrice checks that a price is valid for the given tick size.
@@ -27,8 +27,14 @@ func BuildL0Headers() http.Header {
 // BuildL1Headers returns EIP-712 signed headers for L1 authentication.
 // Used for API key creation and derivation.
 func BuildL1Headers(key *ecdsa.PrivateKey, chainID int, nonce int) (http.Header, error) {
+	return BuildL1HeadersAt(key, chainID, nonce, time.Now())
+}
+
+// BuildL1HeadersAt is like BuildL1Headers but stamps the given time instead of
+// the local clock. Used when auth timestamps are corrected for server drift.
+func BuildL1HeadersAt(key *ecdsa.PrivateKey, chainID int, nonce int, now time.Time) (http.Header, error) {
 	address := crypto.PubkeyToAddress(key.PublicKey)
-	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	timestamp := fmt.Sprintf("%d", now.Unix())
 
 	sig, err := SignClobAuth(key, chainID, address.Hex(), timestamp, nonce)
 	if err != nil {
@@ -54,7 +60,13 @@ type L2Credentials struct {
 // BuildL2Headers returns HMAC-signed headers for L2 authentication.
 // Used for all authenticated API requests (orders, trades, etc.).
 func BuildL2Headers(creds L2Credentials, method, path, body string) (http.Header, error) {
-	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	return BuildL2HeadersAt(creds, method, path, body, time.Now())
+}
+
+// BuildL2HeadersAt is like BuildL2Headers but stamps the given time instead of
+// the local clock. Used when auth timestamps are corrected for server drift.
+func BuildL2HeadersAt(creds L2Credentials, method, path, body string, now time.Time) (http.Header, error) {
+	timestamp := fmt.Sprintf("%d", now.Unix())
 
 	sig, err := BuildHMACSignature(creds.ApiSecret, timestamp, method, path, body)
 	if err != nil {
@@ -0,0 +1,269 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lubluniky/clob-client-go/observe"
+)
+
+// CircuitState is the state of one endpoint group's circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through and counts their failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests immediately with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe through after the cool-down; its
+	// outcome closes the circuit or opens it again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// ErrCircuitOpen is matched by errors from requests refused by an open
+// circuit. Such requests were never sent.
+var ErrCircuitOpen = errors.New("polymarket: circuit open")
+
+// CircuitOpenError is returned for a request refused by an open circuit.
+type CircuitOpenError struct {
+	Group string
+	// RetryAt is when the circuit lets a probe through.
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("polymarket: circuit open for %s until %s", e.Group, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// BreakerConfig configures WithCircuitBreaker. Zero fields take the defaults.
+type BreakerConfig struct {
+	// FailureRate opens the circuit once this share of the attempts in the
+	// window failed. Default 0.5.
+	FailureRate float64
+	// MinRequests is how many attempts the window needs before the failure
+	// rate is considered. Default 20.
+	MinRequests int
+	// Window is how many recent attempts per group the failure rate is
+	// computed over. Default 50.
+	Window int
+	// CoolDown is how long an open circuit refuses requests before letting a
+	// probe through. Default 10s.
+	CoolDown time.Duration
+	// Group maps a request to its endpoint group. Each group has its own
+	// circuit; requests in the empty group bypass the breaker. Default: the
+	// request path.
+	Group func(method, path string) string
+}
+
+// WithCircuitBreaker adds a circuit breaker per endpoint group. Attempts that
+// fail with a network error, a 5xx or a 429 count as failures; other
+// responses count as successes. While a group's circuit is open, its
+// requests, including retries already in progress, fail immediately with a
+// *CircuitOpenError. State changes are reported to observe.Hooks.
+func WithCircuitBreaker(cfg BreakerConfig) Option {
+	return func(c *HTTPClient) {
+		if cfg.FailureRate <= 0 {
+			cfg.FailureRate = 0.5
+		}
+		if cfg.MinRequests <= 0 {
+			cfg.MinRequests = 20
+		}
+		if cfg.Window <= 0 {
+			cfg.Window = 50
+		}
+		cfg.Window = max(cfg.Window, cfg.MinRequests)
+		if cfg.CoolDown <= 0 {
+			cfg.CoolDown = 10 * time.Second
+		}
+		c.breaker = &breaker{cfg: cfg, circuits: make(map[string]*circuit)}
+	}
+}
+
+// CircuitState returns the state of group's circuit. Groups without a
+// breaker, or that have not seen a request, are closed.
+func (c *HTTPClient) CircuitState(group string) CircuitState {
+	return c.breaker.state(group)
+}
+
+// attemptResult is how an attempt counts towards its circuit.
+type attemptResult int
+
+const (
+	attemptSucceeded attemptResult = iota
+	attemptFailed
+	// attemptAbandoned was cancelled by the caller and says nothing about the
+	// server's health.
+	attemptAbandoned
+)
+
+// resultOf classifies an attempt; ctx is the caller's context, so an attempt
+// that hit its own deadline still counts as failed.
+func resultOf(ctx context.Context, resp *http.Response, err error) attemptResult {
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return attemptAbandoned
+	case err != nil:
+		return attemptFailed
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return attemptFailed
+	}
+	return attemptSucceeded
+}
+
+type breaker struct {
+	cfg BreakerConfig
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    CircuitState
+	outcomes []bool // ring of recent attempts; true means failed
+	next     int
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *breaker) group(req *http.Request) string {
+	if b == nil {
+		return ""
+	}
+	if b.cfg.Group != nil {
+		return b.cfg.Group(req.Method, req.URL.Path)
+	}
+	return req.URL.Path
+}
+
+func (b *breaker) state(group string) CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if cb := b.circuits[group]; cb != nil {
+		return cb.state
+	}
+	return CircuitClosed
+}
+
+func (b *breaker) circuit(group string) *circuit {
+	cb := b.circuits[group]
+	if cb == nil {
+		cb = &circuit{}
+		b.circuits[group] = cb
+	}
+	return cb
+}
+
+// allow reports whether an attempt in group may be sent, moving an open
+// circuit whose cool-down has passed to half-open.
+func (b *breaker) allow(group string, hooks observe.Hooks) error {
+	if b == nil || group == "" {
+		return nil
+	}
+	b.mu.Lock()
+	cb := b.circuit(group)
+	switch cb.state {
+	case CircuitOpen:
+		retryAt := cb.openedAt.Add(b.cfg.CoolDown)
+		if time.Now().Before(retryAt) {
+			b.mu.Unlock()
+			return &CircuitOpenError{Group: group, RetryAt: retryAt}
+		}
+		cb.state, cb.probing = CircuitHalfOpen, true
+		b.mu.Unlock()
+		hooks.OnCircuitChange(observe.CircuitChange{Group: group, From: CircuitOpen.String(), To: CircuitHalfOpen.String()})
+		return nil
+	case CircuitHalfOpen:
+		defer b.mu.Unlock()
+		if cb.probing {
+			return &CircuitOpenError{Group: group, RetryAt: time.Now().Add(b.cfg.CoolDown)}
+		}
+		cb.probing = true
+		return nil
+	}
+	b.mu.Unlock()
+	return nil
+}
+
+// record counts an allowed attempt's result and opens or closes the circuit.
+func (b *breaker) record(group string, result attemptResult, cause error, hooks observe.Hooks) {
+	if b == nil || group == "" {
+		return
+	}
+	b.mu.Lock()
+	cb := b.circuit(group)
+	from := cb.state
+	var rate float64
+	switch cb.state {
+	case CircuitClosed:
+		if result == attemptAbandoned {
+			break
+		}
+		if rate = cb.add(result == attemptFailed, b.cfg.Window); len(cb.outcomes) >= b.cfg.MinRequests && rate >= b.cfg.FailureRate {
+			cb.trip()
+		}
+	case CircuitHalfOpen:
+		cb.probing = false
+		switch result {
+		case attemptFailed:
+			cb.trip()
+		case attemptSucceeded:
+			*cb = circuit{}
+		}
+	}
+	to := cb.state
+	b.mu.Unlock()
+
+	if to != from {
+		ev := observe.CircuitChange{Group: group, From: from.String(), To: to.String(), FailureRate: rate}
+		if to == CircuitOpen {
+			ev.Err = cause
+		}
+		hooks.OnCircuitChange(ev)
+	}
+}
+
+// add records one outcome and returns the window's failure rate.
+func (cb *circuit) add(failed bool, window int) float64 {
+	if len(cb.outcomes) < window {
+		cb.outcomes = append(cb.outcomes, failed)
+	} else {
+		if cb.outcomes[cb.next] {
+			cb.failures--
+		}
+		cb.outcomes[cb.next] = failed
+		cb.next = (cb.next + 1) % window
+	}
+	if failed {
+		cb.failures++
+	}
+	return float64(cb.failures) / float64(len(cb.outcomes))
+}
+
+func (cb *circuit) trip() {
+	*cb = circuit{state: CircuitOpen, openedAt: time.Now()}
+}
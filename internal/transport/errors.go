@@ -0,0 +1,279 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorKind classifies an API error by cause.
+type ErrorKind int
+
+const (
+	ErrorKindUnknown ErrorKind = iota
+	ErrorKindAuth
+	ErrorKindRateLimit
+	ErrorKindNotFound
+	ErrorKindInsufficientBalance
+	ErrorKindInvalidTick
+	ErrorKindMarketClosed
+	ErrorKindPostOnlyCross
+	ErrorKindDuplicateOrder
+	ErrorKindInvalidRequest
+	ErrorKindServer
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindAuth:
+		return "auth"
+	case ErrorKindRateLimit:
+		return "rate_limit"
+	case ErrorKindNotFound:
+		return "not_found"
+	case ErrorKindInsufficientBalance:
+		return "insufficient_balance"
+	case ErrorKindInvalidTick:
+		return "invalid_tick"
+	case ErrorKindMarketClosed:
+		return "market_closed"
+	case ErrorKindPostOnlyCross:
+		return "post_only_cross"
+	case ErrorKindDuplicateOrder:
+		return "duplicate_order"
+	case ErrorKindInvalidRequest:
+		return "invalid_request"
+	case ErrorKindServer:
+		return "server"
+	default:
+		return "unknown"
+	}
+}
+
+// Sentinel errors matched by APIError via errors.Is. The parent client
+// package re-exports them.
+var (
+	ErrUnauthorized        = errors.New("polymarket: unauthorized (401)")
+	ErrForbidden           = errors.New("polymarket: forbidden (403)")
+	ErrNotFound            = errors.New("polymarket: not found (404)")
+	ErrRateLimited         = errors.New("polymarket: rate limited (429)")
+	ErrInsufficientBalance = errors.New("polymarket: insufficient balance or allowance")
+	ErrInvalidTickSize     = errors.New("polymarket: price breaks tick size")
+	ErrMarketClosed        = errors.New("polymarket: market closed or not accepting orders")
+	ErrPostOnlyWouldCross  = errors.New("polymarket: post-only order would cross the book")
+	ErrDuplicateOrder      = errors.New("polymarket: duplicate order")
+	ErrServer              = errors.New("polymarket: server error (5xx)")
+)
+
+// APIError is an error response from the CLOB API. It is defined here, rather
+// than in the client package, so the transport can produce it without an
+// import cycle; the client package aliases it.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	// Message is the error text from the body's error/errorMsg/message
+	// field, or the raw body when it is not JSON.
+	Message string
+	// Code is the machine-readable code from the body, when present.
+	Code string
+	// Fields holds any other top-level fields of a JSON error body.
+	Fields map[string]json.RawMessage
+	// Kind classifies the error; errors.Is matches it against the sentinels.
+	Kind ErrorKind
+	// RequestID identifies the request for support, from the response
+	// headers when the server or CDN set one.
+	RequestID string
+	// RetryAfter is the server's Retry-After hint, zero if absent.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("polymarket: %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel for the error's kind and status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || (e.Kind == ErrorKindAuth && e.StatusCode != http.StatusForbidden)
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.Kind == ErrorKindNotFound
+	case ErrRateLimited:
+		return e.Kind == ErrorKindRateLimit
+	case ErrInsufficientBalance:
+		return e.Kind == ErrorKindInsufficientBalance
+	case ErrInvalidTickSize:
+		return e.Kind == ErrorKindInvalidTick
+	case ErrMarketClosed:
+		return e.Kind == ErrorKindMarketClosed
+	case ErrPostOnlyWouldCross:
+		return e.Kind == ErrorKindPostOnlyCross
+	case ErrDuplicateOrder:
+		return e.Kind == ErrorKindDuplicateOrder
+	case ErrServer:
+		return e.Kind == ErrorKindServer
+	}
+	return false
+}
+
+// Retryable reports whether the same request may succeed later: rate limits
+// and server errors.
+func (e *APIError) Retryable() bool {
+	return e.Kind == ErrorKindRateLimit || e.Kind == ErrorKindServer
+}
+
+// maxErrorBody caps how much of an error body is kept for classification.
+const maxErrorBody = 64 << 10
+
+// requestIDHeaders are checked in order for a request identifier.
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid", "X-Amz-Cf-Id", "Cf-Ray"}
+
+// NewAPIError builds a classified APIError from a non-2xx response and its
+// body.
+func NewAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+	}
+	for _, h := range requestIDHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			e.RequestID = v
+			break
+		}
+	}
+	e.RetryAfter = retryAfter(resp)
+	e.parseBody(body)
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	e.Kind = classify(e.StatusCode, e.Code, e.Message)
+	return e
+}
+
+// NewRejection builds a classified APIError for a rejection reported inside a
+// successful response, such as one order of a batch. The message is
+// classified as it would be in a 400 response; StatusCode keeps the actual
+// status.
+func NewRejection(method, path string, status int, msg string) *APIError {
+	return &APIError{
+		StatusCode: status,
+		Method:     method,
+		Path:       path,
+		Message:    msg,
+		Kind:       classify(http.StatusBadRequest, "", msg),
+	}
+}
+
+// parseBody extracts code, message and fields from a JSON error body, or
+// keeps the raw text otherwise.
+func (e *APIError) parseBody(body []byte) {
+	text := strings.TrimSpace(string(body))
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		e.Message = text
+		return
+	}
+	take := func(keys ...string) string {
+		for _, k := range keys {
+			raw, ok := fields[k]
+			if !ok {
+				continue
+			}
+			var s string
+			if json.Unmarshal(raw, &s) != nil {
+				s = string(raw)
+			}
+			delete(fields, k)
+			if s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+	e.Message = take("error", "errorMsg", "error_msg", "message")
+	e.Code = take("code", "errorCode", "error_code")
+	if e.Message == "" {
+		e.Message = text
+	}
+	if len(fields) > 0 {
+		e.Fields = fields
+	}
+}
+
+// classify derives the kind from the status code and the error text. The
+// CLOB reports most order rejections as 400 with a descriptive message.
+func classify(status int, code, msg string) ErrorKind {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorKindRateLimit
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorKindAuth
+	case status >= 500:
+		return ErrorKindServer
+	}
+	text := strings.ToLower(code + " " + msg)
+	has := func(markers ...string) bool {
+		for _, m := range markers {
+			if strings.Contains(text, m) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("rate limit", "too many requests"):
+		return ErrorKindRateLimit
+	case has("unauthorized", "invalid api key", "api key", "invalid signature", "credentials", "l2 auth", "l1 auth"):
+		return ErrorKindAuth
+	case has("not enough balance", "insufficient", "allowance"):
+		return ErrorKindInsufficientBalance
+	case has("post-only", "post only", "postonly", "crosses book", "cross the book", "would cross"):
+		return ErrorKindPostOnlyCross
+	case has("tick size", "tick_size", "minimum tick", "breaks min"):
+		return ErrorKindInvalidTick
+	case has("duplicate", "already exists", "already placed"):
+		return ErrorKindDuplicateOrder
+	case has("market closed", "market is closed", "closed market", "not accepting orders", "not yet ready", "no orderbook exists"),
+		strings.Contains(text, "orderbook") && strings.Contains(text, "does not exist"):
+		return ErrorKindMarketClosed
+	case status == http.StatusNotFound:
+		return ErrorKindNotFound
+	case status >= 400:
+		return ErrorKindInvalidRequest
+	}
+	return ErrorKindUnknown
+}
+
+// retryAfter parses Retry-After as seconds or an HTTP date.
+func retryAfter(resp *http.Response) time.Duration {
+	val := resp.Header.Get("Retry-After")
+	if val == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(val); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(val); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// IsTransient reports whether err is a network failure worth retrying
+// (timeouts, refused or reset connections, temporary DNS failures).
+func IsTransient(err error) bool {
+	return isRetryableError(err)
+}
@@ -11,23 +11,15 @@ import (
 	"math/rand/v2"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
-)
-
-// APIError is defined in transport to avoid circular imports with the parent
-// client package. It mirrors client.APIError.
-type APIError struct {
-	StatusCode int
-	Method     string
-	Path       string
-	Message    string
-}
 
-func (e *APIError) Error() string {
-	return fmt.Sprintf("polymarket: %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
-}
+	"github.com/lubluniky/clob-client-go/observe"
+	"github.com/lubluniky/clob-client-go/replay"
+)
 
 // HTTPClient is a resilient HTTP client with retry logic, exponential backoff,
 // and jitter for communicating with the Polymarket CLOB API.
@@ -37,6 +29,16 @@ type HTTPClient struct {
 	maxRetries int
 	baseDelay  time.Duration
 	maxDelay   time.Duration
+	hooks      observe.Hooks
+	policies   map[string]RetryPolicy
+
+	// Per-attempt deadlines and phase timings; see latency.go.
+	attemptTimeout time.Duration
+	timeouts       map[string]time.Duration
+	timings        bool
+
+	// Per-group circuit breaker; nil when disabled. See breaker.go.
+	breaker *breaker
 }
 
 // Option is a functional option for configuring HTTPClient.
@@ -70,6 +72,32 @@ func WithMaxDelay(d time.Duration) Option {
 	}
 }
 
+// WithHooks installs instrumentation hooks that observe every attempt, retry
+// and response. Secret POLY_* auth headers are redacted before hooks see them.
+func WithHooks(h observe.Hooks) Option {
+	return func(c *HTTPClient) {
+		if h != nil {
+			c.hooks = h
+		}
+	}
+}
+
+// WithRecorder records every HTTP exchange (method, path, query, body, status
+// and response) to rec. It wraps whatever transport is configured so far, so
+// place it after any option that replaces the transport.
+func WithRecorder(rec *replay.Recorder) Option {
+	return func(c *HTTPClient) {
+		c.client.Transport = rec.RoundTripper(c.client.Transport)
+	}
+}
+
+// WithReplay answers every request from a recording instead of the network.
+func WithReplay(rp *replay.Replayer) Option {
+	return func(c *HTTPClient) {
+		c.client.Transport = rp.RoundTripper()
+	}
+}
+
 // NewHTTPClient creates a new HTTPClient with the given base URL and options.
 // Default configuration: timeout=10s, maxRetries=3, baseDelay=100ms, maxDelay=5s.
 func NewHTTPClient(baseURL string, opts ...Option) *HTTPClient {
@@ -79,6 +107,9 @@ func NewHTTPClient(baseURL string, opts ...Option) *HTTPClient {
 		maxRetries: 3,
 		baseDelay:  100 * time.Millisecond,
 		maxDelay:   5 * time.Second,
+		hooks:      observe.Nop{},
+		policies:   make(map[string]RetryPolicy),
+		timeouts:   make(map[string]time.Duration),
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -120,6 +151,24 @@ func (c *HTTPClient) Delete(ctx context.Context, path string, headers http.Heade
 	return c.do(req)
 }
 
+// Send performs a request whose body is already serialized. The bytes are
+// sent exactly as given, so a signature computed over them stays valid. A nil
+// body sends no body.
+func (c *HTTPClient) Send(ctx context.Context, method, path string, headers http.Header, query map[string]string, body []byte) (*http.Response, error) {
+	req, err := c.newRawRequest(ctx, method, path, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		q := req.URL.Query()
+		for k, v := range query {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+	return c.do(req)
+}
+
 // DoJSON executes the given request through the retry-aware client and
 // unmarshals the JSON response body into a value of type T.
 func DoJSON[T any](c *HTTPClient, req *http.Request) (T, error) {
@@ -137,16 +186,7 @@ func DoJSON[T any](c *HTTPClient, req *http.Request) (T, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		msg := strings.TrimSpace(string(body))
-		if msg == "" {
-			msg = http.StatusText(resp.StatusCode)
-		}
-		return zero, &APIError{
-			StatusCode: resp.StatusCode,
-			Method:     req.Method,
-			Path:       req.URL.Path,
-			Message:    msg,
-		}
+		return zero, NewAPIError(resp, body)
 	}
 
 	var result T
@@ -157,7 +197,8 @@ func DoJSON[T any](c *HTTPClient, req *http.Request) (T, error) {
 }
 
 // do executes the HTTP request with retry logic, exponential backoff, and jitter.
-// It buffers the request body upfront so retries can replay it.
+// It buffers the request body upfront so retries can replay it. Which failures
+// are retried depends on the request's RetryPolicy.
 func (c *HTTPClient) do(req *http.Request) (*http.Response, error) {
 	// Buffer the request body so we can replay it on retries.
 	var bodyBytes []byte
@@ -170,10 +211,16 @@ func (c *HTTPClient) do(req *http.Request) (*http.Response, error) {
 		req.Body.Close()
 	}
 
+	ctx := req.Context()
+	policy := c.retryPolicy(req)
+	group := c.breaker.group(req)
 	var lastErr error
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		// Check for context cancellation before each attempt.
-		if err := req.Context().Err(); err != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := c.breaker.allow(group, c.hooks); err != nil {
 			return nil, err
 		}
 
@@ -183,38 +230,103 @@ func (c *HTTPClient) do(req *http.Request) (*http.Response, error) {
 			req.ContentLength = int64(len(bodyBytes))
 		}
 
-		resp, err := c.client.Do(req)
+		attemptCtx := c.hooks.OnRequest(ctx, observe.Request{
+			Method:  req.Method,
+			Path:    req.URL.Path,
+			Query:   req.URL.RawQuery,
+			Header:  observe.RedactHeaders(req.Header),
+			Attempt: attempt,
+		})
+		cancelAttempt := context.CancelFunc(func() {})
+		if d := c.attemptDeadline(req); d > 0 {
+			attemptCtx, cancelAttempt = context.WithTimeout(attemptCtx, d)
+		}
+		var phases *phaseTrace
+		if c.timings {
+			phases = &phaseTrace{}
+			attemptCtx = httptrace.WithClientTrace(attemptCtx, phases.trace())
+		}
+		// Under RetryUnsent, track whether the request left the client so a
+		// failure afterwards is reported as ambiguous rather than retried.
+		var sent atomic.Bool
+		if policy == RetryUnsent {
+			attemptCtx = httptrace.WithClientTrace(attemptCtx, &httptrace.ClientTrace{
+				WroteRequest: func(info httptrace.WroteRequestInfo) { sent.Store(info.Err == nil) },
+			})
+		}
+		start := time.Now()
+		resp, err := c.client.Do(req.WithContext(attemptCtx))
+		c.breaker.record(group, resultOf(ctx, resp, err), err, c.hooks)
 		if err != nil {
-			if !isRetryableError(err) {
+			cancelAttempt()
+			c.hooks.OnResponse(attemptCtx, observe.Response{
+				Method:   req.Method,
+				Path:     req.URL.Path,
+				Attempt:  attempt,
+				Duration: time.Since(start),
+				Err:      err,
+				Timings:  phases.timings(),
+			})
+			if sent.Load() {
+				return nil, outcomeUnknown(err)
+			}
+			if !isRetryableError(err) || policy == RetryNever {
 				return nil, err
 			}
 			lastErr = err
 			if attempt < c.maxRetries {
-				if waitErr := c.backoff(req.Context(), attempt, 0); waitErr != nil {
+				delay := c.backoffDelay(attempt, 0)
+				c.hooks.OnRetry(ctx, observe.Retry{
+					Method:  req.Method,
+					Path:    req.URL.Path,
+					Attempt: attempt,
+					Delay:   delay,
+					Err:     err,
+				})
+				if waitErr := sleep(ctx, delay); waitErr != nil {
 					return nil, waitErr
 				}
 			}
 			continue
 		}
 
-		// Non-retryable status: return immediately.
-		if !isRetryableStatus(resp.StatusCode) {
+		c.hooks.OnResponse(attemptCtx, observe.Response{
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			Attempt:    attempt,
+			StatusCode: resp.StatusCode,
+			Duration:   time.Since(start),
+			Timings:    phases.timings(),
+		})
+
+		// Non-retryable status: return immediately. The attempt deadline
+		// keeps covering the body until the caller closes it.
+		if !isRetryableStatus(resp.StatusCode) || policy == RetryNever {
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancelAttempt}
 			return resp, nil
 		}
 
-		// For retryable statuses, drain and close the body so the
-		// connection can be reused.
+		// For retryable statuses, keep the error body for classification and
+		// drain the rest so the connection can be reused.
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
 		drainBody(resp)
-		lastErr = &APIError{
-			StatusCode: resp.StatusCode,
-			Method:     req.Method,
-			Path:       req.URL.Path,
-			Message:    http.StatusText(resp.StatusCode),
+		cancelAttempt()
+		lastErr = NewAPIError(resp, body)
+		if policy == RetryUnsent && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, outcomeUnknown(lastErr)
 		}
 
 		if attempt < c.maxRetries {
-			retryAfter := parseRetryAfter(resp)
-			if waitErr := c.backoff(req.Context(), attempt, retryAfter); waitErr != nil {
+			delay := c.backoffDelay(attempt, parseRetryAfter(resp))
+			c.hooks.OnRetry(ctx, observe.Retry{
+				Method:     req.Method,
+				Path:       req.URL.Path,
+				Attempt:    attempt,
+				StatusCode: resp.StatusCode,
+				Delay:      delay,
+				Err:        lastErr,
+			})
+			if waitErr := sleep(ctx, delay); waitErr != nil {
 				return nil, waitErr
 			}
 		}
@@ -223,25 +335,26 @@ func (c *HTTPClient) do(req *http.Request) (*http.Response, error) {
 	return nil, fmt.Errorf("polymarket: request failed after %d attempts: %w", c.maxRetries+1, lastErr)
 }
 
-// backoff sleeps for an exponentially increasing duration with jitter, capped
-// at maxDelay. If retryAfterSec is positive (from a Retry-After header), that
-// value is used instead.
-func (c *HTTPClient) backoff(ctx context.Context, attempt int, retryAfterSec int) error {
-	var delay time.Duration
+// backoffDelay returns an exponentially increasing duration with jitter,
+// capped at maxDelay. If retryAfterSec is positive (from a Retry-After header),
+// that value is used instead.
+func (c *HTTPClient) backoffDelay(attempt int, retryAfterSec int) time.Duration {
 	if retryAfterSec > 0 {
-		delay = time.Duration(retryAfterSec) * time.Second
-	} else {
-		// Exponential backoff: baseDelay * 2^attempt.
-		exp := math.Pow(2, float64(attempt))
-		delay = time.Duration(float64(c.baseDelay) * exp)
-		if delay > c.maxDelay {
-			delay = c.maxDelay
-		}
-		// Apply jitter: multiply by a random factor in [0.75, 1.25].
-		jitter := 0.75 + rand.Float64()*0.5
-		delay = time.Duration(float64(delay) * jitter)
+		return time.Duration(retryAfterSec) * time.Second
+	}
+	// Exponential backoff: baseDelay * 2^attempt.
+	exp := math.Pow(2, float64(attempt))
+	delay := time.Duration(float64(c.baseDelay) * exp)
+	if delay > c.maxDelay {
+		delay = c.maxDelay
 	}
+	// Apply jitter: multiply by a random factor in [0.75, 1.25].
+	jitter := 0.75 + rand.Float64()*0.5
+	return time.Duration(float64(delay) * jitter)
+}
 
+// sleep waits for delay or until ctx is cancelled.
+func sleep(ctx context.Context, delay time.Duration) error {
 	timer := time.NewTimer(delay)
 	defer timer.Stop()
 
@@ -267,6 +380,18 @@ func parseRetryAfter(resp *http.Response) int {
 	return secs
 }
 
+// cancelOnClose releases an attempt's context when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
 // drainBody reads and closes the response body so the underlying connection
 // can be returned to the pool.
 func drainBody(resp *http.Response) {
@@ -323,6 +448,20 @@ func isRetryableStatus(code int) bool {
 
 // newRequest builds an *http.Request with the full URL, JSON body, and headers.
 func (c *HTTPClient) newRequest(ctx context.Context, method, path string, headers http.Header, body interface{}) (*http.Request, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("polymarket: marshalling request body: %w", err)
+		}
+	}
+	return c.newRawRequest(ctx, method, path, headers, data)
+}
+
+// newRawRequest builds an *http.Request with the full URL, serialized body,
+// and headers.
+func (c *HTTPClient) newRawRequest(ctx context.Context, method, path string, headers http.Header, body []byte) (*http.Request, error) {
 	if path != "" && path[0] != '/' {
 		path = "/" + path
 	}
@@ -330,11 +469,7 @@ func (c *HTTPClient) newRequest(ctx context.Context, method, path string, header
 
 	var bodyReader io.Reader
 	if body != nil {
-		data, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("polymarket: marshalling request body: %w", err)
-		}
-		bodyReader = bytes.NewReader(data)
+		bodyReader = bytes.NewReader(body)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
@@ -360,8 +495,7 @@ func (c *HTTPClient) newRequest(ctx context.Context, method, path string, header
 
 // ParseResponse reads the response body and checks for API errors.
 // On success (2xx), it returns the raw body bytes.
-// On error, it returns an *APIError with the status code, method, path, and
-// message extracted from the response body.
+// On error, it returns a classified *APIError; see NewAPIError.
 func ParseResponse(resp *http.Response) ([]byte, error) {
 	defer resp.Body.Close()
 
@@ -373,16 +507,5 @@ func ParseResponse(resp *http.Response) ([]byte, error) {
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		return body, nil
 	}
-
-	msg := strings.TrimSpace(string(body))
-	if msg == "" {
-		msg = http.StatusText(resp.StatusCode)
-	}
-
-	return nil, &APIError{
-		StatusCode: resp.StatusCode,
-		Method:     resp.Request.Method,
-		Path:       resp.Request.URL.Path,
-		Message:    msg,
-	}
+	return nil, NewAPIError(resp, body)
 }
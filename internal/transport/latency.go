@@ -0,0 +1,177 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/lubluniky/clob-client-go/observe"
+)
+
+// Low-latency transport settings.
+const (
+	lowLatencyDialTimeout   = 3 * time.Second
+	lowLatencyKeepAlive     = 15 * time.Second
+	lowLatencyIdleTimeout   = 5 * time.Minute
+	lowLatencyIdlePerHost   = 16
+	lowLatencyPingInterval  = 15 * time.Second
+	lowLatencyPingTimeout   = 5 * time.Second
+	lowLatencyAttemptBudget = 2 * time.Second
+)
+
+// WithLowLatency switches to a transport tuned for order entry: HTTP/2 with
+// ping health checks so a dead connection is detected before an order is sent
+// on it, a large long-lived idle pool, short dial and TLS timeouts, and phase
+// timings on every response. The global client timeout is replaced by a
+// per-attempt deadline (see WithAttemptTimeout) so a slow attempt is retried
+// instead of consuming the whole budget. Call Warm once at startup to open
+// connections before the first order.
+//
+// Place it before WithRecorder, which wraps the transport configured so far.
+func WithLowLatency() Option {
+	return func(c *HTTPClient) {
+		c.client.Transport = newLowLatencyTransport()
+		c.client.Timeout = 0
+		c.attemptTimeout = lowLatencyAttemptBudget
+		c.timings = true
+	}
+}
+
+func newLowLatencyTransport() *http.Transport {
+	dialer := &net.Dialer{Timeout: lowLatencyDialTimeout, KeepAlive: lowLatencyKeepAlive}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = dialer.DialContext
+	t.ForceAttemptHTTP2 = true
+	t.TLSHandshakeTimeout = lowLatencyDialTimeout
+	t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	t.MaxIdleConns = 4 * lowLatencyIdlePerHost
+	t.MaxIdleConnsPerHost = lowLatencyIdlePerHost
+	t.IdleConnTimeout = lowLatencyIdleTimeout
+	t.ExpectContinueTimeout = 0
+	t.HTTP2 = &http.HTTP2Config{
+		SendPingTimeout: lowLatencyPingInterval,
+		PingTimeout:     lowLatencyPingTimeout,
+	}
+	return t
+}
+
+// WithAttemptTimeout bounds each attempt, including reading the response
+// body, separately from the client-wide timeout. Zero disables it.
+func WithAttemptTimeout(d time.Duration) Option {
+	return func(c *HTTPClient) {
+		c.attemptTimeout = d
+	}
+}
+
+// WithEndpointTimeout overrides the attempt timeout for requests with the
+// given method and path, e.g. a tighter deadline for order posts.
+func WithEndpointTimeout(method, path string, d time.Duration) Option {
+	return func(c *HTTPClient) {
+		c.timeouts[policyKey(method, path)] = d
+	}
+}
+
+// WithPhaseTimings collects DNS, connect, TLS and time-to-first-byte timings
+// for every attempt and reports them in observe.Response.Timings.
+func WithPhaseTimings(enabled bool) Option {
+	return func(c *HTTPClient) {
+		c.timings = enabled
+	}
+}
+
+func (c *HTTPClient) attemptDeadline(req *http.Request) time.Duration {
+	if d, ok := c.timeouts[policyKey(req.Method, req.URL.Path)]; ok {
+		return d
+	}
+	return c.attemptTimeout
+}
+
+// Warm opens up to n connections to the API host, so the first requests skip
+// DNS, TCP and TLS setup. Over HTTP/2 a single connection carries every
+// request, so n > 1 only matters when the server negotiates HTTP/1.1.
+func (c *HTTPClient) Warm(ctx context.Context, n int) error {
+	n = max(n, 1)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/", nil)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			resp, err := c.client.Do(req)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			drainBody(resp)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// phaseTrace records observe.Timings for one attempt. Callbacks may run on
+// transport goroutines, so fields are guarded.
+type phaseTrace struct {
+	mu                               sync.Mutex
+	dnsStart, connectStart, tlsStart time.Time
+	wrote                            time.Time
+	t                                observe.Timings
+}
+
+func (p *phaseTrace) trace() *httptrace.ClientTrace {
+	lock := func(f func()) {
+		p.mu.Lock()
+		f()
+		p.mu.Unlock()
+	}
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { lock(func() { p.dnsStart = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { lock(func() { p.t.DNS = time.Since(p.dnsStart) }) },
+		ConnectStart: func(string, string) {
+			lock(func() {
+				if p.connectStart.IsZero() {
+					p.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			lock(func() {
+				if err == nil && p.t.Connect == 0 {
+					p.t.Connect = time.Since(p.connectStart)
+				}
+			})
+		},
+		TLSHandshakeStart: func() { lock(func() { p.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			lock(func() { p.t.TLS = time.Since(p.tlsStart) })
+		},
+		GotConn:      func(info httptrace.GotConnInfo) { lock(func() { p.t.Reused = info.Reused }) },
+		WroteRequest: func(httptrace.WroteRequestInfo) { lock(func() { p.wrote = time.Now() }) },
+		GotFirstResponseByte: func() {
+			lock(func() {
+				if !p.wrote.IsZero() {
+					p.t.TTFB = time.Since(p.wrote)
+				}
+			})
+		},
+	}
+}
+
+func (p *phaseTrace) timings() observe.Timings {
+	if p == nil {
+		return observe.Timings{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.t
+}
@@ -0,0 +1,49 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// RetryPolicy controls which failed attempts of a request are retried.
+type RetryPolicy int
+
+const (
+	// RetryIdempotent retries rate limits, server errors and transient
+	// network failures. It is the default for every request.
+	RetryIdempotent RetryPolicy = iota
+	// RetryUnsent retries only failures where the server cannot have acted
+	// on the request: rate limits, and network errors before the request was
+	// fully written. Any other failure is returned as an error matching
+	// ErrOutcomeUnknown, so the caller can reconcile instead of resending.
+	RetryUnsent
+	// RetryNever makes a single attempt.
+	RetryNever
+)
+
+// ErrOutcomeUnknown is matched by errors from requests under RetryUnsent that
+// failed after the server may have processed them: a timeout or dropped
+// connection after the request was sent, or a 5xx response.
+var ErrOutcomeUnknown = errors.New("polymarket: request outcome unknown")
+
+// WithRetryPolicy sets the retry policy for requests with the given method
+// and path. Paths are matched exactly, without the query string.
+func WithRetryPolicy(method, path string, p RetryPolicy) Option {
+	return func(c *HTTPClient) {
+		c.policies[policyKey(method, path)] = p
+	}
+}
+
+func policyKey(method, path string) string {
+	return method + " " + path
+}
+
+func (c *HTTPClient) retryPolicy(req *http.Request) RetryPolicy {
+	return c.policies[policyKey(req.Method, req.URL.Path)]
+}
+
+// outcomeUnknown wraps err so it matches both ErrOutcomeUnknown and err.
+func outcomeUnknown(err error) error {
+	return fmt.Errorf("%w: %w", ErrOutcomeUnknown, err)
+}
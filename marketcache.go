@@ -0,0 +1,488 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MarketEventType identifies a market lifecycle change seen by MarketCache.
+type MarketEventType string
+
+const (
+	// MarketOpened: a market listed after the initial load, or a known market
+	// that reopened or started accepting orders.
+	MarketOpened MarketEventType = "opened"
+	// MarketClosed: the market closed.
+	MarketClosed MarketEventType = "closed"
+	// MarketStoppedAccepting: the market stopped accepting orders.
+	MarketStoppedAccepting MarketEventType = "stopped_accepting"
+	// MarketResolved: a token was marked as the winner.
+	MarketResolved MarketEventType = "resolved"
+	// MarketTickSizeChanged: the minimum tick size changed.
+	MarketTickSizeChanged MarketEventType = "tick_size_changed"
+	// MarketRemoved: a full Load no longer listed the market, so it was
+	// dropped from the cache. Market is its last known state.
+	MarketRemoved MarketEventType = "removed"
+)
+
+// MarketEvent describes one change to a cached market.
+type MarketEvent struct {
+	Type MarketEventType
+	// Market is the state after the change.
+	Market Market
+	// Previous is the state before the change; nil for new listings.
+	Previous *Market
+	// TokenID is the winning token for MarketResolved and the token whose
+	// tick size changed for MarketTickSizeChanged, when known.
+	TokenID string
+	Time    time.Time
+}
+
+// Default MarketCache refresh cadence.
+const (
+	DefaultMarketRefreshInterval = time.Minute
+	DefaultMarketReloadInterval  = 15 * time.Minute
+)
+
+// MarketCacheOption configures a MarketCache.
+type MarketCacheOption func(*MarketCache)
+
+// WithMarketEventHandler sets the callback receiving lifecycle events. It is
+// called synchronously, after the cache is updated, from the goroutine that
+// applied the change.
+func WithMarketEventHandler(fn func(MarketEvent)) MarketCacheOption {
+	return func(m *MarketCache) { m.onEvent = fn }
+}
+
+// WithMarketRefreshInterval sets how often Run picks up newly listed
+// markets. Default DefaultMarketRefreshInterval.
+func WithMarketRefreshInterval(d time.Duration) MarketCacheOption {
+	return func(m *MarketCache) {
+		if d > 0 {
+			m.refreshEvery = d
+		}
+	}
+}
+
+// WithMarketReloadInterval sets how often Run re-reads every market to catch
+// status changes on existing ones. Default DefaultMarketReloadInterval.
+func WithMarketReloadInterval(d time.Duration) MarketCacheOption {
+	return func(m *MarketCache) {
+		if d > 0 {
+			m.reloadEvery = d
+		}
+	}
+}
+
+// WithMarketRefreshErrorHandler receives refresh errors from Run, which
+// otherwise keeps the previous data and retries on the next tick.
+func WithMarketRefreshErrorHandler(fn func(error)) MarketCacheOption {
+	return func(m *MarketCache) { m.onError = fn }
+}
+
+// MarketCache holds market metadata indexed by condition ID, token ID, slug
+// and tag. It keeps the client's tick size and neg-risk caches in sync, so
+// order building uses the same values, and reports lifecycle changes as
+// MarketEvents. It is safe for concurrent use.
+type MarketCache struct {
+	client       *ClobClient
+	onEvent      func(MarketEvent)
+	onError      func(error)
+	refreshEvery time.Duration
+	reloadEvery  time.Duration
+
+	mu      sync.RWMutex
+	markets map[string]*Market         // condition ID -> market
+	byToken map[string]string          // token ID -> condition ID
+	bySlug  map[string]string          // slug -> condition ID
+	byTag   map[string]map[string]bool // tag -> condition IDs
+	loaded  bool
+	// resume is the cursor of the last (partial) page, where new listings
+	// appear.
+	resume string
+}
+
+// NewMarketCache returns an empty cache backed by c. Call Load or Run to fill
+// it.
+func NewMarketCache(c *ClobClient, opts ...MarketCacheOption) *MarketCache {
+	m := &MarketCache{
+		client:       c,
+		refreshEvery: DefaultMarketRefreshInterval,
+		reloadEvery:  DefaultMarketReloadInterval,
+		markets:      make(map[string]*Market),
+		byToken:      make(map[string]string),
+		bySlug:       make(map[string]string),
+		byTag:        make(map[string]map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Load reads every market and applies the differences. Markets no longer
+// listed are dropped from the cache and its indexes. The first Load emits no
+// events; later calls emit events for every change found.
+func (m *MarketCache) Load(ctx context.Context) error {
+	return m.pageFrom(ctx, "")
+}
+
+// Refresh picks up markets listed since the last Load or Refresh by resuming
+// pagination from the last page, instead of re-reading the whole universe.
+func (m *MarketCache) Refresh(ctx context.Context) error {
+	m.mu.RLock()
+	loaded, cursor := m.loaded, m.resume
+	m.mu.RUnlock()
+	if !loaded {
+		return m.Load(ctx)
+	}
+	return m.pageFrom(ctx, cursor)
+}
+
+// RefreshMarket re-reads one market, for example after an order was rejected
+// because the market closed.
+func (m *MarketCache) RefreshMarket(ctx context.Context, conditionID string) error {
+	market, err := m.client.GetMarket(ctx, conditionID)
+	if err != nil {
+		return err
+	}
+	m.apply([]Market{*market})
+	return nil
+}
+
+// RefreshSimplified updates token prices and winners from the lighter
+// simplified markets listing, reporting resolutions without re-reading full
+// market objects. Unknown markets are skipped.
+func (m *MarketCache) RefreshSimplified(ctx context.Context) error {
+	var events []MarketEvent
+	for sm, err := range m.client.GetSimplifiedMarkets(ctx) {
+		if err != nil {
+			m.emit(events)
+			return err
+		}
+		m.mu.Lock()
+		if prev, ok := m.markets[sm.ConditionID]; ok {
+			next := *prev
+			next.Tokens = sm.Tokens
+			events = append(events, m.upsertLocked(next, time.Now())...)
+		}
+		m.mu.Unlock()
+	}
+	m.emit(events)
+	return nil
+}
+
+// Run loads the cache if needed and keeps it fresh until ctx ends: Refresh on
+// the refresh interval and a full Load on the reload interval. It returns
+// ctx.Err(), or the initial load error.
+func (m *MarketCache) Run(ctx context.Context) error {
+	m.mu.RLock()
+	loaded := m.loaded
+	m.mu.RUnlock()
+	if !loaded {
+		if err := m.Load(ctx); err != nil {
+			return err
+		}
+	}
+	refresh := time.NewTicker(m.refreshEvery)
+	defer refresh.Stop()
+	reload := time.NewTicker(m.reloadEvery)
+	defer reload.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-refresh.C:
+			err = m.Refresh(ctx)
+		case <-reload.C:
+			err = m.Load(ctx)
+		}
+		if err != nil && ctx.Err() == nil && m.onError != nil {
+			m.onError(err)
+		}
+	}
+}
+
+// pageFrom reads markets starting at cursor, applying each page as it
+// arrives and remembering where the last page started. Reading from the
+// first page lists every market, so it also evicts the ones not seen.
+func (m *MarketCache) pageFrom(ctx context.Context, cursor string) error {
+	var listed map[string]bool
+	if cursor == "" {
+		listed = make(map[string]bool)
+	}
+	for {
+		page, err := getPage[Market](ctx, m.client, EndpointMarkets, cursor, "markets")
+		if err != nil {
+			return err
+		}
+		m.apply(page.Data)
+		if listed != nil {
+			for _, market := range page.Data {
+				listed[market.ConditionID] = true
+			}
+		}
+		if page.NextCursor == "" || page.NextCursor == "LTE=" {
+			var events []MarketEvent
+			m.mu.Lock()
+			if listed != nil {
+				events = m.evictLocked(listed, time.Now())
+			}
+			m.loaded = true
+			m.resume = cursor
+			m.mu.Unlock()
+			m.emit(events)
+			return nil
+		}
+		cursor = page.NextCursor
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// apply upserts markets and emits the resulting events. Events are
+// suppressed until the first full load completes.
+func (m *MarketCache) apply(markets []Market) {
+	now := time.Now()
+	var events []MarketEvent
+	m.mu.Lock()
+	loaded := m.loaded
+	for _, market := range markets {
+		ev := m.upsertLocked(market, now)
+		if loaded {
+			events = append(events, ev...)
+		}
+	}
+	m.mu.Unlock()
+	m.emit(events)
+}
+
+// upsertLocked stores next, reindexes it, syncs the client caches and
+// returns the lifecycle events relative to the previous version.
+func (m *MarketCache) upsertLocked(next Market, now time.Time) []MarketEvent {
+	if next.ConditionID == "" {
+		return nil
+	}
+	prev, known := m.markets[next.ConditionID]
+	if known {
+		m.unindexLocked(prev)
+	}
+	stored := next
+	m.markets[next.ConditionID] = &stored
+	m.indexLocked(&stored)
+	m.syncClientLocked(prev, &stored)
+
+	if !known {
+		if next.AcceptingOrders && !next.Closed {
+			return []MarketEvent{{Type: MarketOpened, Market: stored, Time: now}}
+		}
+		return nil
+	}
+
+	var events []MarketEvent
+	add := func(t MarketEventType, tokenID string) {
+		events = append(events, MarketEvent{Type: t, Market: stored, Previous: prev, TokenID: tokenID, Time: now})
+	}
+	switch {
+	case !prev.Closed && next.Closed:
+		add(MarketClosed, "")
+	case prev.AcceptingOrders && !next.AcceptingOrders:
+		add(MarketStoppedAccepting, "")
+	case (prev.Closed || !prev.AcceptingOrders) && !next.Closed && next.AcceptingOrders:
+		add(MarketOpened, "")
+	}
+	if !prev.MinimumTickSize.Equal(next.MinimumTickSize) {
+		add(MarketTickSizeChanged, "")
+	}
+	for _, tok := range next.Tokens {
+		if tok.Winner && !prevWinner(prev, tok.TokenID) {
+			add(MarketResolved, tok.TokenID)
+		}
+	}
+	return events
+}
+
+// evictLocked drops the markets not in listed and returns their
+// MarketRemoved events, or none before the first full load.
+func (m *MarketCache) evictLocked(listed map[string]bool, now time.Time) []MarketEvent {
+	var removed []string
+	for id := range m.markets {
+		if !listed[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	var events []MarketEvent
+	for _, id := range removed {
+		market := m.markets[id]
+		m.unindexLocked(market)
+		delete(m.markets, id)
+		if m.loaded {
+			events = append(events, MarketEvent{Type: MarketRemoved, Market: *market, Time: now})
+		}
+	}
+	return events
+}
+
+func prevWinner(m *Market, tokenID string) bool {
+	for _, tok := range m.Tokens {
+		if tok.TokenID == tokenID {
+			return tok.Winner
+		}
+	}
+	return false
+}
+
+func (m *MarketCache) indexLocked(market *Market) {
+	for _, tok := range market.Tokens {
+		m.byToken[tok.TokenID] = market.ConditionID
+	}
+	if market.MarketSlug != "" {
+		m.bySlug[market.MarketSlug] = market.ConditionID
+	}
+	for _, tag := range market.Tags {
+		if m.byTag[tag] == nil {
+			m.byTag[tag] = make(map[string]bool)
+		}
+		m.byTag[tag][market.ConditionID] = true
+	}
+}
+
+func (m *MarketCache) unindexLocked(market *Market) {
+	for _, tok := range market.Tokens {
+		delete(m.byToken, tok.TokenID)
+	}
+	if m.bySlug[market.MarketSlug] == market.ConditionID {
+		delete(m.bySlug, market.MarketSlug)
+	}
+	for _, tag := range market.Tags {
+		delete(m.byTag[tag], market.ConditionID)
+		if len(m.byTag[tag]) == 0 {
+			delete(m.byTag, tag)
+		}
+	}
+}
+
+// syncClientLocked seeds the client's per-token tick size and neg-risk
+// caches and drops cached fee rates when the market's fees change.
+func (m *MarketCache) syncClientLocked(prev, next *Market) {
+	c := m.client
+	now := time.Now()
+	feesChanged := prev != nil && (!prev.TakerBaseFee.Equal(next.TakerBaseFee) || !prev.MakerBaseFee.Equal(next.MakerBaseFee))
+	for _, tok := range next.Tokens {
+		if !next.MinimumTickSize.IsZero() {
+			c.meta.tickSizes.Store(tok.TokenID, next.MinimumTickSize.String())
+			c.meta.tickSizesLoaded.Store(tok.TokenID, now)
+		}
+		c.meta.negRisk.Store(tok.TokenID, next.NegRisk)
+		if feesChanged {
+			c.meta.feeRates.Delete(tok.TokenID)
+		}
+	}
+}
+
+// ApplyTickSizeChange records a tick size change pushed by the market
+// WebSocket channel (ws.TickSizeChange) for tokenID. Use ws.Client's
+// ApplyTickSizeChanges to feed it automatically.
+func (m *MarketCache) ApplyTickSizeChange(tokenID, tickSize string) error {
+	tick, err := decimal.NewFromString(tickSize)
+	if err != nil {
+		return fmt.Errorf("polymarket: parsing tick size %q: %w", tickSize, err)
+	}
+	m.client.meta.tickSizes.Store(tokenID, tick.String())
+	m.client.meta.tickSizesLoaded.Store(tokenID, time.Now())
+
+	m.mu.Lock()
+	id, ok := m.byToken[tokenID]
+	if !ok {
+		m.mu.Unlock()
+		return nil
+	}
+	prev := m.markets[id]
+	if prev.MinimumTickSize.Equal(tick) {
+		m.mu.Unlock()
+		return nil
+	}
+	next := *prev
+	next.MinimumTickSize = tick
+	m.markets[id] = &next
+	loaded := m.loaded
+	m.mu.Unlock()
+
+	if loaded {
+		m.emit([]MarketEvent{{Type: MarketTickSizeChanged, Market: next, Previous: prev, TokenID: tokenID, Time: time.Now()}})
+	}
+	return nil
+}
+
+func (m *MarketCache) emit(events []MarketEvent) {
+	if m.onEvent == nil {
+		return
+	}
+	for _, ev := range events {
+		m.onEvent(ev)
+	}
+}
+
+// Market returns the market with the given condition ID. The returned value
+// shares its slices with the cache and must be treated as read-only.
+func (m *MarketCache) Market(conditionID string) (Market, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if market, ok := m.markets[conditionID]; ok {
+		return *market, true
+	}
+	return Market{}, false
+}
+
+// MarketByToken returns the market containing tokenID.
+func (m *MarketCache) MarketByToken(tokenID string) (Market, bool) {
+	m.mu.RLock()
+	id, ok := m.byToken[tokenID]
+	m.mu.RUnlock()
+	if !ok {
+		return Market{}, false
+	}
+	return m.Market(id)
+}
+
+// MarketBySlug returns the market with the given slug.
+func (m *MarketCache) MarketBySlug(slug string) (Market, bool) {
+	m.mu.RLock()
+	id, ok := m.bySlug[slug]
+	m.mu.RUnlock()
+	if !ok {
+		return Market{}, false
+	}
+	return m.Market(id)
+}
+
+// MarketsByTag returns the markets carrying tag, sorted by condition ID.
+func (m *MarketCache) MarketsByTag(tag string) []Market {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]string, 0, len(m.byTag[tag]))
+	for id := range m.byTag[tag] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := make([]Market, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, *m.markets[id])
+	}
+	return out
+}
+
+// Len returns the number of cached markets.
+func (m *MarketCache) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.markets)
+}
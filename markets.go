@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"iter"
+	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -52,38 +54,14 @@ func (c *ClobClient) ServerTime(ctx context.Context) (int64, error) {
 // GetMarkets returns an iterator over all markets with auto-pagination.
 func (c *ClobClient) GetMarkets(ctx context.Context) iter.Seq2[Market, error] {
 	return paginate[Market](ctx, func(cursor string) (PaginatedResponse[Market], error) {
-		query := map[string]string{}
-		if cursor != "" {
-			query["next_cursor"] = cursor
-		}
-		raw, err := c.getJSON(ctx, EndpointMarkets, query)
-		if err != nil {
-			return PaginatedResponse[Market]{}, err
-		}
-		var page PaginatedResponse[Market]
-		if err := json.Unmarshal(raw, &page); err != nil {
-			return PaginatedResponse[Market]{}, fmt.Errorf("polymarket: parsing markets: %w", err)
-		}
-		return page, nil
+		return getPage[Market](ctx, c, EndpointMarkets, cursor, "markets")
 	})
 }
 
 // GetSamplingMarkets returns an iterator over sampling markets.
 func (c *ClobClient) GetSamplingMarkets(ctx context.Context) iter.Seq2[Market, error] {
 	return paginate[Market](ctx, func(cursor string) (PaginatedResponse[Market], error) {
-		query := map[string]string{}
-		if cursor != "" {
-			query["next_cursor"] = cursor
-		}
-		raw, err := c.getJSON(ctx, EndpointSamplingMarkets, query)
-		if err != nil {
-			return PaginatedResponse[Market]{}, err
-		}
-		var page PaginatedResponse[Market]
-		if err := json.Unmarshal(raw, &page); err != nil {
-			return PaginatedResponse[Market]{}, fmt.Errorf("polymarket: parsing sampling markets: %w", err)
-		}
-		return page, nil
+		return getPage[Market](ctx, c, EndpointSamplingMarkets, cursor, "sampling markets")
 	})
 }
 
@@ -103,44 +81,38 @@ func (c *ClobClient) GetMarket(ctx context.Context, conditionID string) (*Market
 // GetSimplifiedMarkets returns an iterator over simplified markets.
 func (c *ClobClient) GetSimplifiedMarkets(ctx context.Context) iter.Seq2[SimplifiedMarket, error] {
 	return paginate[SimplifiedMarket](ctx, func(cursor string) (PaginatedResponse[SimplifiedMarket], error) {
-		query := map[string]string{}
-		if cursor != "" {
-			query["next_cursor"] = cursor
-		}
-		raw, err := c.getJSON(ctx, EndpointSimplifiedMarkets, query)
-		if err != nil {
-			return PaginatedResponse[SimplifiedMarket]{}, err
-		}
-		var page PaginatedResponse[SimplifiedMarket]
-		if err := json.Unmarshal(raw, &page); err != nil {
-			return PaginatedResponse[SimplifiedMarket]{}, fmt.Errorf("polymarket: parsing simplified markets: %w", err)
-		}
-		return page, nil
+		return getPage[SimplifiedMarket](ctx, c, EndpointSimplifiedMarkets, cursor, "simplified markets")
 	})
 }
 
 // GetSamplingSimplifiedMarkets returns an iterator over sampling simplified markets.
 func (c *ClobClient) GetSamplingSimplifiedMarkets(ctx context.Context) iter.Seq2[SimplifiedMarket, error] {
 	return paginate[SimplifiedMarket](ctx, func(cursor string) (PaginatedResponse[SimplifiedMarket], error) {
-		query := map[string]string{}
-		if cursor != "" {
-			query["next_cursor"] = cursor
-		}
-		raw, err := c.getJSON(ctx, EndpointSamplingSimplifiedMarkets, query)
-		if err != nil {
-			return PaginatedResponse[SimplifiedMarket]{}, err
-		}
-		var page PaginatedResponse[SimplifiedMarket]
-		if err := json.Unmarshal(raw, &page); err != nil {
-			return PaginatedResponse[SimplifiedMarket]{}, fmt.Errorf("polymarket: parsing sampling simplified markets: %w", err)
-		}
-		return page, nil
+		return getPage[SimplifiedMarket](ctx, c, EndpointSamplingSimplifiedMarkets, cursor, "sampling simplified markets")
 	})
 }
 
+// getPage fetches one page of a cursor-paginated public endpoint. what names
+// the resource in parse errors.
+func getPage[T any](ctx context.Context, c *ClobClient, endpoint, cursor, what string) (PaginatedResponse[T], error) {
+	query := map[string]string{}
+	if cursor != "" {
+		query["next_cursor"] = cursor
+	}
+	raw, err := c.getJSON(ctx, endpoint, query)
+	if err != nil {
+		return PaginatedResponse[T]{}, err
+	}
+	var page PaginatedResponse[T]
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return PaginatedResponse[T]{}, fmt.Errorf("polymarket: parsing %s: %w", what, err)
+	}
+	return page, nil
+}
+
 // GetOrderBook returns the order book for a token.
 func (c *ClobClient) GetOrderBook(ctx context.Context, tokenID string) (*OrderBookSummary, error) {
-	raw, err := c.getJSON(ctx, EndpointOrderBook, map[string]string{"token_id": tokenID})
+	raw, err := c.hedgedGet(ctx, EndpointOrderBook, map[string]string{"token_id": tokenID})
 	if err != nil {
 		return nil, err
 	}
@@ -154,18 +126,13 @@ func (c *ClobClient) GetOrderBook(ctx context.Context, tokenID string) (*OrderBo
 
 // GetOrderBooks returns order books for multiple tokens.
 func (c *ClobClient) GetOrderBooks(ctx context.Context, params []BookParams) ([]OrderBookSummary, error) {
-	resp, err := c.http.Post(ctx, EndpointOrderBooks, c.l0Headers(), params)
-	if err != nil {
-		return nil, err
-	}
-	data, err := transport.ParseResponse(resp)
+	parts, err := fanOut(ctx, c, params, func(ctx context.Context, chunk []BookParams) ([]OrderBookSummary, error) {
+		return call[[]OrderBookSummary](ctx, c, apiRequest{method: http.MethodPost, path: EndpointOrderBooks, body: chunk}, "order books")
+	})
 	if err != nil {
 		return nil, err
 	}
-	var result []OrderBookSummary
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing order books: %w", err)
-	}
+	result := slices.Concat(parts...)
 	for _, ob := range result {
 		c.updateTickSizeFromOrderBook(ob)
 	}
@@ -174,7 +141,7 @@ func (c *ClobClient) GetOrderBooks(ctx context.Context, params []BookParams) ([]
 
 // GetMidpoint returns the midpoint price for a token.
 func (c *ClobClient) GetMidpoint(ctx context.Context, tokenID string) (decimal.Decimal, error) {
-	raw, err := c.getJSON(ctx, EndpointMidpoint, map[string]string{"token_id": tokenID})
+	raw, err := c.hedgedGet(ctx, EndpointMidpoint, map[string]string{"token_id": tokenID})
 	if err != nil {
 		return decimal.Zero, err
 	}
@@ -187,7 +154,7 @@ func (c *ClobClient) GetMidpoint(ctx context.Context, tokenID string) (decimal.D
 
 // GetPrice returns the best price for a given side.
 func (c *ClobClient) GetPrice(ctx context.Context, tokenID string, side Side) (decimal.Decimal, error) {
-	raw, err := c.getJSON(ctx, EndpointPrice, map[string]string{
+	raw, err := c.hedgedGet(ctx, EndpointPrice, map[string]string{
 		"token_id": tokenID,
 		"side":     string(side),
 	})
@@ -237,19 +204,7 @@ func (c *ClobClient) GetMidpoints(ctx context.Context, tokenIDs []string) (map[s
 	for _, tokenID := range tokenIDs {
 		body = append(body, BookParams{TokenID: tokenID})
 	}
-	resp, err := c.http.Post(ctx, EndpointMidpoints, c.l0Headers(), body)
-	if err != nil {
-		return nil, err
-	}
-	data, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-	var result map[string]string
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing midpoints: %w", err)
-	}
-	return result, nil
+	return batchRead[string](ctx, c, EndpointMidpoints, body, "midpoints")
 }
 
 // GetPrices returns best prices for multiple tokens.
@@ -258,19 +213,7 @@ func (c *ClobClient) GetPrices(ctx context.Context, tokenIDs []string, side Side
 	for _, tokenID := range tokenIDs {
 		body = append(body, BookParams{TokenID: tokenID, Side: side})
 	}
-	resp, err := c.http.Post(ctx, EndpointPrices, c.l0Headers(), body)
-	if err != nil {
-		return nil, err
-	}
-	data, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-	var result map[string]string
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing prices: %w", err)
-	}
-	return result, nil
+	return batchRead[string](ctx, c, EndpointPrices, body, "prices")
 }
 
 // GetSpreads returns spreads for multiple tokens.
@@ -279,19 +222,7 @@ func (c *ClobClient) GetSpreads(ctx context.Context, tokenIDs []string) (map[str
 	for _, tokenID := range tokenIDs {
 		body = append(body, BookParams{TokenID: tokenID})
 	}
-	resp, err := c.http.Post(ctx, EndpointSpreads, c.l0Headers(), body)
-	if err != nil {
-		return nil, err
-	}
-	data, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-	var result map[string]SpreadResponse
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing spreads: %w", err)
-	}
-	return result, nil
+	return batchRead[SpreadResponse](ctx, c, EndpointSpreads, body, "spreads")
 }
 
 // GetLastTradesPrices returns last trade prices for multiple tokens.
@@ -300,19 +231,7 @@ func (c *ClobClient) GetLastTradesPrices(ctx context.Context, tokenIDs []string)
 	for _, tokenID := range tokenIDs {
 		body = append(body, BookParams{TokenID: tokenID})
 	}
-	resp, err := c.http.Post(ctx, EndpointLastTradesPrices, c.l0Headers(), body)
-	if err != nil {
-		return nil, err
-	}
-	data, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-	var result map[string]string
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing last trade prices: %w", err)
-	}
-	return result, nil
+	return batchRead[string](ctx, c, EndpointLastTradesPrices, body, "last trade prices")
 }
 
 // GetPricesHistory returns historical prices for market filters.
@@ -410,19 +329,19 @@ func (c *ClobClient) GetOrderBookHash(orderbook *OrderBookSummary) (string, erro
 // ClearTickSizeCache invalidates cached tick sizes for a token or all tokens.
 func (c *ClobClient) ClearTickSizeCache(tokenID ...string) {
 	if len(tokenID) == 0 {
-		c.tickSizes.Range(func(k, _ interface{}) bool {
-			c.tickSizes.Delete(k)
+		c.meta.tickSizes.Range(func(k, _ interface{}) bool {
+			c.meta.tickSizes.Delete(k)
 			return true
 		})
-		c.tickSizesLoaded.Range(func(k, _ interface{}) bool {
-			c.tickSizesLoaded.Delete(k)
+		c.meta.tickSizesLoaded.Range(func(k, _ interface{}) bool {
+			c.meta.tickSizesLoaded.Delete(k)
 			return true
 		})
 		return
 	}
 	for _, id := range tokenID {
-		c.tickSizes.Delete(id)
-		c.tickSizesLoaded.Delete(id)
+		c.meta.tickSizes.Delete(id)
+		c.meta.tickSizesLoaded.Delete(id)
 	}
 }
 
@@ -432,11 +351,11 @@ func (c *ClobClient) ClearTickSizeCache(tokenID ...string) {
 
 // GetTickSize returns the tick size for a token (cached after first lookup).
 func (c *ClobClient) GetTickSize(ctx context.Context, tokenID string) (string, error) {
-	if v, ok := c.tickSizes.Load(tokenID); ok {
+	if v, ok := c.meta.tickSizes.Load(tokenID); ok {
 		if c.tickSizeTTL <= 0 {
 			return v.(string), nil
 		}
-		if ts, okTS := c.tickSizesLoaded.Load(tokenID); okTS {
+		if ts, okTS := c.meta.tickSizesLoaded.Load(tokenID); okTS {
 			if loadedAt, okT := ts.(time.Time); okT && time.Since(loadedAt) <= c.tickSizeTTL {
 				return v.(string), nil
 			}
@@ -454,14 +373,14 @@ func (c *ClobClient) GetTickSize(ctx context.Context, tokenID string) (string, e
 		return "", fmt.Errorf("polymarket: parsing tick size: %w", err)
 	}
 	ts := resp.MinimumTickSize.String()
-	c.tickSizes.Store(tokenID, ts)
-	c.tickSizesLoaded.Store(tokenID, time.Now())
+	c.meta.tickSizes.Store(tokenID, ts)
+	c.meta.tickSizesLoaded.Store(tokenID, time.Now())
 	return ts, nil
 }
 
 // GetNegRisk returns whether a token uses neg-risk (cached after first lookup).
 func (c *ClobClient) GetNegRisk(ctx context.Context, tokenID string) (bool, error) {
-	if v, ok := c.negRisk.Load(tokenID); ok {
+	if v, ok := c.meta.negRisk.Load(tokenID); ok {
 		return v.(bool), nil
 	}
 	raw, err := c.getJSON(ctx, EndpointNegRisk, map[string]string{"token_id": tokenID})
@@ -475,13 +394,13 @@ func (c *ClobClient) GetNegRisk(ctx context.Context, tokenID string) (bool, erro
 	if err := json.Unmarshal(raw, &resp); err != nil {
 		return false, fmt.Errorf("polymarket: parsing neg risk: %w", err)
 	}
-	c.negRisk.Store(tokenID, resp.NegRisk)
+	c.meta.negRisk.Store(tokenID, resp.NegRisk)
 	return resp.NegRisk, nil
 }
 
 // GetFeeRateBps returns the fee rate in basis points for a token (cached after first lookup).
 func (c *ClobClient) GetFeeRateBps(ctx context.Context, tokenID string) (string, error) {
-	if v, ok := c.feeRates.Load(tokenID); ok {
+	if v, ok := c.meta.feeRates.Load(tokenID); ok {
 		return v.(string), nil
 	}
 	raw, err := c.getJSON(ctx, EndpointFeeRate, map[string]string{"token_id": tokenID})
@@ -496,7 +415,7 @@ func (c *ClobClient) GetFeeRateBps(ctx context.Context, tokenID string) (string,
 		return "", fmt.Errorf("polymarket: parsing fee rate: %w", err)
 	}
 	fr := resp.BaseFee.String()
-	c.feeRates.Store(tokenID, fr)
+	c.meta.feeRates.Store(tokenID, fr)
 	return fr, nil
 }
 
@@ -504,6 +423,6 @@ func (c *ClobClient) updateTickSizeFromOrderBook(ob OrderBookSummary) {
 	if ob.AssetID == "" || ob.TickSize == "" {
 		return
 	}
-	c.tickSizes.Store(ob.AssetID, ob.TickSize)
-	c.tickSizesLoaded.Store(ob.AssetID, time.Now())
+	c.meta.tickSizes.Store(ob.AssetID, ob.TickSize)
+	c.meta.tickSizesLoaded.Store(ob.AssetID, time.Now())
 }
@@ -0,0 +1,211 @@
+// Package observe defines instrumentation hooks for the REST transport and
+// the WebSocket client, along with ready-made adapters for structured
+// logging (log/slog), tracing spans and counter/histogram metrics.
+package observe
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Request describes one HTTP attempt about to be sent.
+type Request struct {
+	Method  string
+	Path    string
+	Query   string
+	Header  http.Header // secrets redacted
+	Attempt int         // 0 for the first attempt
+}
+
+// Response describes the outcome of one HTTP attempt.
+type Response struct {
+	Method     string
+	Path       string
+	Attempt    int
+	StatusCode int // 0 when the attempt failed before a response was read
+	Duration   time.Duration
+	Err        error
+	// Timings breaks the attempt into phases when the transport collects
+	// them (transport.WithPhaseTimings); otherwise it is zero.
+	Timings Timings
+}
+
+// Timings breaks one HTTP attempt into phases. Phases that did not happen,
+// such as DNS, Connect and TLS on a reused connection, are zero.
+type Timings struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// TTFB is the time from the request being written to the first
+	// response byte: server processing plus one round trip.
+	TTFB time.Duration
+	// Reused reports whether the attempt ran on a pooled connection.
+	Reused bool
+}
+
+// Retry describes a retry decision after a failed attempt.
+type Retry struct {
+	Method     string
+	Path       string
+	Attempt    int // the attempt that failed
+	StatusCode int // 0 for network errors
+	Delay      time.Duration
+	Err        error
+}
+
+// WSConnect describes a successful WebSocket dial.
+type WSConnect struct {
+	URL     string
+	Attempt int // dial attempts since the last successful connection
+}
+
+// WSDisconnect describes a dropped WebSocket connection.
+type WSDisconnect struct {
+	URL       string
+	Uptime    time.Duration
+	Err       error
+	Reconnect bool // false when the client is shutting down
+}
+
+// WSMessage describes one inbound WebSocket event.
+type WSMessage struct {
+	URL       string
+	EventType string
+	Size      int
+}
+
+// CircuitChange describes a circuit breaker state transition for a group of
+// endpoints. States are "closed", "open" and "half-open".
+type CircuitChange struct {
+	Group       string
+	From        string
+	To          string
+	FailureRate float64 // failure rate that opened the circuit, 0 otherwise
+	Err         error   // network error of the attempt that opened it, if any
+}
+
+// ClockSync describes one server clock synchronisation.
+type ClockSync struct {
+	Offset time.Duration // server time minus local time
+	RTT    time.Duration // round trip of the sample the offset came from
+	Err    error         // set when no sample succeeded; Offset and RTT are then zero
+}
+
+// Hooks receives instrumentation callbacks. Implementations must be safe for
+// concurrent use and should return quickly; they run on the request and read
+// goroutines.
+//
+// OnRequest may return a derived context (for example carrying a span); the
+// matching OnResponse receives that context.
+type Hooks interface {
+	OnRequest(ctx context.Context, req Request) context.Context
+	OnRetry(ctx context.Context, retry Retry)
+	OnResponse(ctx context.Context, resp Response)
+	OnWSConnect(ev WSConnect)
+	OnWSDisconnect(ev WSDisconnect)
+	OnWSMessage(ev WSMessage)
+	OnCircuitChange(ev CircuitChange)
+	OnClockSync(ev ClockSync)
+}
+
+// Nop is a Hooks implementation that does nothing. Embed it to implement only
+// the callbacks you need.
+type Nop struct{}
+
+func (Nop) OnRequest(ctx context.Context, _ Request) context.Context { return ctx }
+func (Nop) OnRetry(context.Context, Retry)                           {}
+func (Nop) OnResponse(context.Context, Response)                     {}
+func (Nop) OnWSConnect(WSConnect)                                    {}
+func (Nop) OnWSDisconnect(WSDisconnect)                              {}
+func (Nop) OnWSMessage(WSMessage)                                    {}
+func (Nop) OnCircuitChange(CircuitChange)                            {}
+func (Nop) OnClockSync(ClockSync)                                    {}
+
+// Multi fans every callback out to each of the given hooks in order.
+func Multi(hooks ...Hooks) Hooks {
+	filtered := make(multi, 0, len(hooks))
+	for _, h := range hooks {
+		if h != nil {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered
+}
+
+type multi []Hooks
+
+func (m multi) OnRequest(ctx context.Context, req Request) context.Context {
+	for _, h := range m {
+		ctx = h.OnRequest(ctx, req)
+	}
+	return ctx
+}
+
+func (m multi) OnRetry(ctx context.Context, retry Retry) {
+	for _, h := range m {
+		h.OnRetry(ctx, retry)
+	}
+}
+
+func (m multi) OnResponse(ctx context.Context, resp Response) {
+	for _, h := range m {
+		h.OnResponse(ctx, resp)
+	}
+}
+
+func (m multi) OnWSConnect(ev WSConnect) {
+	for _, h := range m {
+		h.OnWSConnect(ev)
+	}
+}
+
+func (m multi) OnWSDisconnect(ev WSDisconnect) {
+	for _, h := range m {
+		h.OnWSDisconnect(ev)
+	}
+}
+
+func (m multi) OnWSMessage(ev WSMessage) {
+	for _, h := range m {
+		h.OnWSMessage(ev)
+	}
+}
+
+func (m multi) OnCircuitChange(ev CircuitChange) {
+	for _, h := range m {
+		h.OnCircuitChange(ev)
+	}
+}
+
+func (m multi) OnClockSync(ev ClockSync) {
+	for _, h := range m {
+		h.OnClockSync(ev)
+	}
+}
+
+// Redacted is the placeholder written in place of secret header values.
+const Redacted = "[REDACTED]"
+
+// secretHeaders are the POLY_* auth headers whose values must never be logged.
+var secretHeaders = map[string]struct{}{
+	"POLY_SIGNATURE":  {},
+	"POLY_API_KEY":    {},
+	"POLY_PASSPHRASE": {},
+}
+
+// RedactHeaders returns a copy of h with secret POLY_* auth header values
+// replaced by Redacted. Non-secret headers such as POLY_ADDRESS and
+// POLY_TIMESTAMP are kept.
+func RedactHeaders(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, vals := range h {
+		if _, ok := secretHeaders[strings.ToUpper(k)]; ok {
+			out[k] = []string{Redacted}
+			continue
+		}
+		out[k] = append([]string(nil), vals...)
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package observe
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("POLY_ADDRESS", "0xabc")
+	h.Set("POLY_SIGNATURE", "sig")
+	h.Set("POLY_API_KEY", "key")
+	h.Set("POLY_PASSPHRASE", "pass")
+	h.Set("POLY_TIMESTAMP", "1")
+
+	got := RedactHeaders(h)
+	for _, k := range []string{"POLY_SIGNATURE", "POLY_API_KEY", "POLY_PASSPHRASE"} {
+		if v := got.Get(k); v != Redacted {
+			t.Fatalf("%s not redacted: %q", k, v)
+		}
+	}
+	if got.Get("POLY_ADDRESS") != "0xabc" || got.Get("POLY_TIMESTAMP") != "1" {
+		t.Fatalf("non-secret headers altered: %v", got)
+	}
+	if h.Get("POLY_SIGNATURE") != "sig" {
+		t.Fatalf("original header mutated")
+	}
+}
+
+type countingHooks struct {
+	Nop
+	responses int
+}
+
+func (h *countingHooks) OnResponse(context.Context, Response) { h.responses++ }
+
+func TestMultiFansOut(t *testing.T) {
+	a, b := &countingHooks{}, &countingHooks{}
+	m := Multi(a, nil, b)
+	ctx := m.OnRequest(context.Background(), Request{})
+	m.OnResponse(ctx, Response{})
+	if a.responses != 1 || b.responses != 1 {
+		t.Fatalf("expected both hooks called once, got %d and %d", a.responses, b.responses)
+	}
+}
+
+func TestMetricsHooksTemplatePaths(t *testing.T) {
+	var labels []string
+	h := &MetricsHooks{Requests: CounterFunc(func(lvs ...string) { labels = append(labels, strings.Join(lvs, " ")) })}
+	for _, path := range []string{"/data/order/0xabc", "/data/order/0xdef", "/markets/0x123", "/v1/heartbeats", "/rewards/markets/42"} {
+		h.OnResponse(context.Background(), Response{Method: http.MethodGet, Path: path, StatusCode: http.StatusOK})
+	}
+	want := "GET /data/order/:id 200,GET /data/order/:id 200,GET /markets/:id 200,GET /v1/heartbeats 200,GET /rewards/markets/:id 200"
+	if got := strings.Join(labels, ","); got != want {
+		t.Fatalf("unexpected labels:\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMetricsHooksClockOffset(t *testing.T) {
+	var offset float64
+	var results []string
+	h := &MetricsHooks{
+		ClockOffset: GaugeFunc(func(v float64, _ ...string) { offset = v }),
+		ClockSyncs:  CounterFunc(func(lvs ...string) { results = append(results, lvs...) }),
+	}
+	h.OnClockSync(ClockSync{Offset: 1500 * time.Millisecond, RTT: time.Millisecond})
+	h.OnClockSync(ClockSync{Err: context.DeadlineExceeded})
+	if offset != 1.5 || strings.Join(results, ",") != "ok,error" {
+		t.Fatalf("unexpected offset %v results %v", offset, results)
+	}
+}
+
+type recordingSpan struct{ attrs map[string]any }
+
+func (s *recordingSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *recordingSpan) RecordError(error)                  {}
+func (s *recordingSpan) End()                               {}
+
+type recordingTracer struct {
+	names []string
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{attrs: map[string]any{}}
+	t.names = append(t.names, name)
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestTraceHooksNameSpansByRoute(t *testing.T) {
+	tracer := &recordingTracer{}
+	h := NewTraceHooks(tracer)
+	ctx := h.OnRequest(context.Background(), Request{Method: http.MethodGet, Path: "/data/order/0xabc"})
+	h.OnResponse(ctx, Response{StatusCode: http.StatusOK})
+	if len(tracer.names) != 1 || tracer.names[0] != "polymarket GET /data/order/:id" {
+		t.Fatalf("unexpected span names %v", tracer.names)
+	}
+	if attrs := tracer.spans[0].attrs; attrs["url.path"] != "/data/order/0xabc" || attrs["http.route"] != "/data/order/:id" {
+		t.Fatalf("unexpected attributes %v", attrs)
+	}
+}
@@ -0,0 +1,180 @@
+package observe
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Counter is a labelled monotonic counter, e.g. a thin wrapper around a
+// Prometheus CounterVec: func(lvs ...string) { vec.WithLabelValues(lvs...).Inc() }.
+type Counter interface {
+	Inc(labels ...string)
+}
+
+// Histogram is a labelled distribution, e.g. a thin wrapper around a
+// Prometheus HistogramVec.
+type Histogram interface {
+	Observe(value float64, labels ...string)
+}
+
+// Gauge is a labelled value that goes up and down, e.g. a thin wrapper
+// around a Prometheus GaugeVec.
+type Gauge interface {
+	Set(value float64, labels ...string)
+}
+
+// CounterFunc adapts a function to Counter.
+type CounterFunc func(labels ...string)
+
+func (f CounterFunc) Inc(labels ...string) { f(labels...) }
+
+// HistogramFunc adapts a function to Histogram.
+type HistogramFunc func(value float64, labels ...string)
+
+func (f HistogramFunc) Observe(value float64, labels ...string) { f(value, labels...) }
+
+// GaugeFunc adapts a function to Gauge.
+type GaugeFunc func(value float64, labels ...string)
+
+func (f GaugeFunc) Set(value float64, labels ...string) { f(value, labels...) }
+
+// MetricsHooks records Prometheus-style metrics. Any nil field is skipped.
+// Label orders are documented per field so vectors can be declared to match.
+// Path labels are route templates, not raw paths, so order and market IDs do
+// not create a series each.
+type MetricsHooks struct {
+	// Route maps a request path to its path label. Default RouteTemplate.
+	Route func(path string) string
+
+	// Requests counts completed HTTP attempts. Labels: method, path, status.
+	// Status is "error" for attempts that failed without a response.
+	Requests Counter
+	// Retries counts retries. Labels: method, path.
+	Retries Counter
+	// Latency observes attempt duration in seconds. Labels: method, path.
+	Latency Histogram
+	// Phases observes the duration in seconds of each phase that occurred
+	// when phase timings are collected. Labels: method, path, phase (dns,
+	// connect, tls, ttfb).
+	Phases Histogram
+	// WSConnects counts successful WS dials. Labels: url.
+	WSConnects Counter
+	// WSDisconnects counts dropped WS connections. Labels: url.
+	WSDisconnects Counter
+	// WSMessages counts inbound WS events. Labels: url, event_type.
+	WSMessages Counter
+	// CircuitChanges counts circuit breaker transitions. Labels: group, state
+	// (the new state).
+	CircuitChanges Counter
+	// ClockOffset is set to the server clock offset (server minus local
+	// time) in seconds after each successful clock sync. No labels.
+	ClockOffset Gauge
+	// ClockSyncs counts clock syncs. Labels: result (ok, error).
+	ClockSyncs Counter
+}
+
+func (h *MetricsHooks) OnRequest(ctx context.Context, _ Request) context.Context { return ctx }
+
+func (h *MetricsHooks) OnRetry(_ context.Context, retry Retry) {
+	if h.Retries != nil {
+		h.Retries.Inc(retry.Method, h.route(retry.Path))
+	}
+}
+
+func (h *MetricsHooks) OnResponse(_ context.Context, resp Response) {
+	path := h.route(resp.Path)
+	if h.Requests != nil {
+		status := "error"
+		if resp.StatusCode != 0 {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		h.Requests.Inc(resp.Method, path, status)
+	}
+	if h.Latency != nil {
+		h.Latency.Observe(resp.Duration.Seconds(), resp.Method, path)
+	}
+	if h.Phases != nil {
+		t := resp.Timings
+		for _, p := range []struct {
+			name string
+			d    time.Duration
+		}{{"dns", t.DNS}, {"connect", t.Connect}, {"tls", t.TLS}, {"ttfb", t.TTFB}} {
+			if p.d > 0 {
+				h.Phases.Observe(p.d.Seconds(), resp.Method, path, p.name)
+			}
+		}
+	}
+}
+
+func (h *MetricsHooks) route(path string) string {
+	if h.Route != nil {
+		return h.Route(path)
+	}
+	return RouteTemplate(path)
+}
+
+// RouteTemplate replaces the ID segments of an API path, those that are
+// 0x-prefixed or numeric like order, condition and token IDs, with ":id":
+// "/data/order/0xabc" becomes "/data/order/:id".
+func RouteTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if isIDSegment(seg) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isIDSegment(seg string) bool {
+	if strings.HasPrefix(seg, "0x") || strings.HasPrefix(seg, "0X") {
+		return true
+	}
+	if seg == "" {
+		return false
+	}
+	for _, r := range seg {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *MetricsHooks) OnWSConnect(ev WSConnect) {
+	if h.WSConnects != nil {
+		h.WSConnects.Inc(ev.URL)
+	}
+}
+
+func (h *MetricsHooks) OnWSDisconnect(ev WSDisconnect) {
+	if h.WSDisconnects != nil {
+		h.WSDisconnects.Inc(ev.URL)
+	}
+}
+
+func (h *MetricsHooks) OnWSMessage(ev WSMessage) {
+	if h.WSMessages != nil {
+		h.WSMessages.Inc(ev.URL, ev.EventType)
+	}
+}
+
+func (h *MetricsHooks) OnCircuitChange(ev CircuitChange) {
+	if h.CircuitChanges != nil {
+		h.CircuitChanges.Inc(ev.Group, ev.To)
+	}
+}
+
+func (h *MetricsHooks) OnClockSync(ev ClockSync) {
+	result := "ok"
+	if ev.Err != nil {
+		result = "error"
+	} else if h.ClockOffset != nil {
+		h.ClockOffset.Set(ev.Offset.Seconds())
+	}
+	if h.ClockSyncs != nil {
+		h.ClockSyncs.Inc(result)
+	}
+}
@@ -0,0 +1,115 @@
+package observe
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHooks logs requests, retries, responses and WebSocket lifecycle events
+// to a *slog.Logger. Requests and individual WS messages are logged at Debug,
+// connects and circuit recoveries at Info, and retries, failed responses,
+// disconnects and opened circuits at Warn.
+type SlogHooks struct {
+	Logger *slog.Logger
+	// LogMessages enables per-message Debug logs for WS traffic. It is off by
+	// default because market streams can be very high volume.
+	LogMessages bool
+}
+
+// NewSlogHooks returns hooks that log to logger (slog.Default() if nil).
+func NewSlogHooks(logger *slog.Logger) *SlogHooks {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogHooks{Logger: logger}
+}
+
+func (h *SlogHooks) OnRequest(ctx context.Context, req Request) context.Context {
+	h.Logger.LogAttrs(ctx, slog.LevelDebug, "polymarket: http request",
+		slog.String("method", req.Method),
+		slog.String("path", req.Path),
+		slog.String("query", req.Query),
+		slog.Int("attempt", req.Attempt),
+		slog.Any("headers", req.Header),
+	)
+	return ctx
+}
+
+func (h *SlogHooks) OnRetry(ctx context.Context, retry Retry) {
+	h.Logger.LogAttrs(ctx, slog.LevelWarn, "polymarket: http retry",
+		slog.String("method", retry.Method),
+		slog.String("path", retry.Path),
+		slog.Int("attempt", retry.Attempt),
+		slog.Int("status", retry.StatusCode),
+		slog.Duration("delay", retry.Delay),
+		slog.Any("error", retry.Err),
+	)
+}
+
+func (h *SlogHooks) OnResponse(ctx context.Context, resp Response) {
+	level := slog.LevelDebug
+	if resp.Err != nil || resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	h.Logger.LogAttrs(ctx, level, "polymarket: http response",
+		slog.String("method", resp.Method),
+		slog.String("path", resp.Path),
+		slog.Int("attempt", resp.Attempt),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", resp.Duration),
+		slog.Any("error", resp.Err),
+	)
+}
+
+func (h *SlogHooks) OnWSConnect(ev WSConnect) {
+	h.Logger.LogAttrs(context.Background(), slog.LevelInfo, "polymarket: ws connected",
+		slog.String("url", ev.URL),
+		slog.Int("attempt", ev.Attempt),
+	)
+}
+
+func (h *SlogHooks) OnWSDisconnect(ev WSDisconnect) {
+	h.Logger.LogAttrs(context.Background(), slog.LevelWarn, "polymarket: ws disconnected",
+		slog.String("url", ev.URL),
+		slog.Duration("uptime", ev.Uptime),
+		slog.Bool("reconnect", ev.Reconnect),
+		slog.Any("error", ev.Err),
+	)
+}
+
+func (h *SlogHooks) OnClockSync(ev ClockSync) {
+	if ev.Err != nil {
+		h.Logger.LogAttrs(context.Background(), slog.LevelWarn, "polymarket: clock sync failed",
+			slog.Any("error", ev.Err),
+		)
+		return
+	}
+	h.Logger.LogAttrs(context.Background(), slog.LevelDebug, "polymarket: clock synced",
+		slog.Duration("offset", ev.Offset),
+		slog.Duration("rtt", ev.RTT),
+	)
+}
+
+func (h *SlogHooks) OnWSMessage(ev WSMessage) {
+	if !h.LogMessages {
+		return
+	}
+	h.Logger.LogAttrs(context.Background(), slog.LevelDebug, "polymarket: ws message",
+		slog.String("url", ev.URL),
+		slog.String("event_type", ev.EventType),
+		slog.Int("size", ev.Size),
+	)
+}
+
+func (h *SlogHooks) OnCircuitChange(ev CircuitChange) {
+	level := slog.LevelInfo
+	if ev.To == "open" {
+		level = slog.LevelWarn
+	}
+	h.Logger.LogAttrs(context.Background(), level, "polymarket: circuit "+ev.To,
+		slog.String("group", ev.Group),
+		slog.String("from", ev.From),
+		slog.Float64("failure_rate", ev.FailureRate),
+		slog.Any("error", ev.Err),
+	)
+}
@@ -0,0 +1,63 @@
+package observe
+
+import "context"
+
+// Span is the subset of an OpenTelemetry-style span used by TraceHooks.
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans. Adapting an OpenTelemetry trace.Tracer takes a few
+// lines: start the span, wrap it to satisfy Span, and return the new context.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type spanKey struct{}
+
+// TraceHooks opens one span per HTTP attempt, named "polymarket METHOD route",
+// and ends it when the attempt completes. The route is the path's template,
+// as in MetricsHooks, so IDs do not make span names unbounded; the raw path
+// is kept in url.path. Retried attempts get their own span with
+// http.request.resend_count set, following the OpenTelemetry HTTP
+// conventions. WebSocket events are not traced.
+type TraceHooks struct {
+	Nop
+	Tracer Tracer
+	// Route maps a request path to its route. Default RouteTemplate.
+	Route func(path string) string
+}
+
+// NewTraceHooks returns hooks that trace HTTP attempts with tracer.
+func NewTraceHooks(tracer Tracer) *TraceHooks {
+	return &TraceHooks{Tracer: tracer}
+}
+
+func (h *TraceHooks) OnRequest(ctx context.Context, req Request) context.Context {
+	route := RouteTemplate(req.Path)
+	if h.Route != nil {
+		route = h.Route(req.Path)
+	}
+	ctx, span := h.Tracer.Start(ctx, "polymarket "+req.Method+" "+route)
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("http.route", route)
+	span.SetAttribute("url.path", req.Path)
+	span.SetAttribute("http.request.resend_count", req.Attempt)
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (h *TraceHooks) OnResponse(ctx context.Context, resp Response) {
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return
+	}
+	if resp.StatusCode != 0 {
+		span.SetAttribute("http.response.status_code", resp.StatusCode)
+	}
+	if resp.Err != nil {
+		span.RecordError(resp.Err)
+	}
+	span.End()
+}
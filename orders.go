@@ -3,16 +3,18 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"iter"
+	"net/http"
 	"strconv"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/shopspring/decimal"
 
 	"github.com/lubluniky/clob-client-go/internal/orderbuilder"
-	"github.com/lubluniky/clob-client-go/internal/transport"
 )
 
 // sideToInt converts a Side enum to the integer representation expected by the
@@ -81,6 +83,13 @@ func (c *ClobClient) CreateOrder(ctx context.Context, args OrderArgs) (*SignedOr
 		taker = ZeroAddress
 	}
 	sigType := c.resolveSignatureType(args.SignatureType)
+	if err := c.validateFunder(sigType); err != nil {
+		return nil, err
+	}
+	expiration := args.Expiration
+	if expiration == 0 && args.TTL > 0 {
+		expiration = c.GTDExpiration(args.TTL)
+	}
 
 	signerAddr := crypto.PubkeyToAddress(c.signer.PublicKey)
 	makerAddr := c.address
@@ -98,7 +107,7 @@ func (c *ClobClient) CreateOrder(ctx context.Context, args OrderArgs) (*SignedOr
 		FeeRateBps:    fmt.Sprintf("%d", resolvedFeeRate),
 		Nonce:         fmt.Sprintf("%d", args.Nonce),
 		Signer:        signerAddr,
-		Expiration:    fmt.Sprintf("%d", args.Expiration),
+		Expiration:    fmt.Sprintf("%d", expiration),
 		SignatureType: int(sigType),
 		Salt:          orderbuilder.GenerateSalt(),
 	}
@@ -171,6 +180,9 @@ func (c *ClobClient) CreateMarketOrder(ctx context.Context, args MarketOrderArgs
 		taker = ZeroAddress
 	}
 	sigType := c.resolveSignatureType(args.SignatureType)
+	if err := c.validateFunder(sigType); err != nil {
+		return nil, err
+	}
 
 	signerAddr := crypto.PubkeyToAddress(c.signer.PublicKey)
 	makerAddr := c.address
@@ -218,14 +230,31 @@ func (c *ClobClient) CreateMarketOrder(ctx context.Context, args MarketOrderArgs
 // PostOrder submits a signed order to the API. The orderType controls execution
 // strategy (GTC, FOK, GTD, FAK). When postOnly is true the order will only be
 // accepted if it would rest on the book (no immediate match).
+//
+// The post is never blindly retried. If it fails after the request was sent,
+// the order is looked up by its hash and only resent, unchanged, if the
+// exchange does not know it; see WithOrderResends. This reconciliation runs
+// even if ctx has ended, under a timeout of its own.
 func (c *ClobClient) PostOrder(ctx context.Context, order SignedOrder, orderType OrderType, postOnly bool) (*OrderResponse, error) {
 	if postOnly && orderType != GTC && orderType != GTD {
 		return nil, &ValidationError{Field: "postOnly", Message: "postOnly is only supported for GTC and GTD orders"}
 	}
 
+	resp, err := c.postOrder(ctx, order, orderType, postOnly)
+	if errors.Is(err, ErrOutcomeUnknown) {
+		rctx, cancel := reconcileContext(ctx)
+		defer cancel()
+		return c.reconcileOrder(rctx, order, err, func() (*OrderResponse, error) {
+			return c.postOrder(rctx, order, orderType, postOnly)
+		})
+	}
+	return resp, err
+}
+
+func (c *ClobClient) postOrder(ctx context.Context, order SignedOrder, orderType OrderType, postOnly bool) (*OrderResponse, error) {
 	owner := ""
-	if c.creds != nil {
-		owner = c.creds.ApiKey
+	if creds := c.ApiCreds(); creds != nil {
+		owner = creds.ApiKey
 	}
 
 	req := PostOrderRequest{
@@ -235,36 +264,74 @@ func (c *ClobClient) PostOrder(ctx context.Context, order SignedOrder, orderType
 		PostOnly:  postOnly,
 	}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("polymarket: marshalling order request: %w", err)
-	}
-
-	headers, err := c.l2Headers("POST", EndpointPostOrder, string(body))
+	result, err := call[OrderResponse](ctx, c, apiRequest{method: http.MethodPost, path: EndpointPostOrder, body: req, auth: authL2}, "order response")
 	if err != nil {
 		return nil, err
 	}
+	result.normalizeIDs()
+	return &result, nil
+}
 
-	resp, err := c.http.Post(ctx, EndpointPostOrder, headers, req)
-	if err != nil {
-		return nil, err
+// PostOrders submits a batch of signed orders and reports the outcome of each
+// by input index. Batches larger than MaxBatchSize are split into chunks that
+// are posted concurrently; see WithBatchConcurrency. Like PostOrder, a chunk
+// whose outcome is unknown is reconciled order by order rather than resent
+// whole.
+//
+// Orders that were rejected, or whose chunk failed, carry a classified error
+// in their outcome; use BatchResult.Err to check for any. The error is
+// non-nil for invalid arguments, and when a chunk's request failed as a
+// whole, e.g. with ErrUnauthorized or ErrCircuitOpen; the result then still
+// holds the outcomes of every order.
+func (c *ClobClient) PostOrders(ctx context.Context, args []PostOrdersArgs, deferExec bool, defaultPostOnly bool) (*BatchResult, error) {
+	for _, arg := range args {
+		postOnly := defaultPostOnly
+		if arg.PostOnly != nil {
+			postOnly = *arg.PostOnly
+		}
+		if postOnly && arg.OrderType != GTC && arg.OrderType != GTD {
+			return nil, &ValidationError{Field: "postOnly", Message: "postOnly is only supported for GTC and GTD orders"}
+		}
 	}
 
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
+	result := &BatchResult{Outcomes: make([]OrderOutcome, len(args))}
+	chunkErrs := make([]error, (len(args)+MaxBatchSize-1)/MaxBatchSize)
+	sem := make(chan struct{}, c.batchConcurrency)
+	var wg sync.WaitGroup
+	for start := 0; start < len(args); start += MaxBatchSize {
+		chunk := args[start:min(start+MaxBatchSize, len(args))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			resps, errs, err := c.postOrderChunk(ctx, chunk, deferExec, defaultPostOnly)
+			result.fill(start, len(chunk), resps, errs, err)
+			if err != nil {
+				chunkErrs[start/MaxBatchSize] = fmt.Errorf("polymarket: posting orders %d-%d: %w", start, start+len(chunk)-1, err)
+			}
+		}()
 	}
+	wg.Wait()
+	return result, errors.Join(chunkErrs...)
+}
 
-	var result OrderResponse
-	if err := json.Unmarshal(raw, &result); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing order response: %w", err)
+// postOrderChunk posts at most MaxBatchSize orders, reconciling an unknown
+// outcome. errs is set only after reconciliation and holds the errors of the
+// orders it left unsettled; otherwise err fails the whole chunk.
+func (c *ClobClient) postOrderChunk(ctx context.Context, args []PostOrdersArgs, deferExec bool, defaultPostOnly bool) ([]OrderResponse, []error, error) {
+	results, err := c.postOrders(ctx, args, deferExec, defaultPostOnly)
+	if errors.Is(err, ErrOutcomeUnknown) {
+		rctx, cancel := reconcileContext(ctx)
+		defer cancel()
+		return c.reconcileOrders(rctx, args, err, func(pending []PostOrdersArgs) ([]OrderResponse, error) {
+			return c.postOrders(rctx, pending, deferExec, defaultPostOnly)
+		})
 	}
-	result.normalizeIDs()
-	return &result, nil
+	return results, nil, err
 }
 
-// PostOrders submits a batch of signed orders.
-func (c *ClobClient) PostOrders(ctx context.Context, args []PostOrdersArgs, deferExec bool, defaultPostOnly bool) ([]OrderResponse, error) {
+func (c *ClobClient) postOrders(ctx context.Context, args []PostOrdersArgs, deferExec bool, defaultPostOnly bool) ([]OrderResponse, error) {
 	type batchOrderRequest struct {
 		Order     SignedOrder `json:"order"`
 		Owner     string      `json:"owner"`
@@ -274,8 +341,8 @@ func (c *ClobClient) PostOrders(ctx context.Context, args []PostOrdersArgs, defe
 	}
 
 	owner := ""
-	if c.creds != nil {
-		owner = c.creds.ApiKey
+	if creds := c.ApiCreds(); creds != nil {
+		owner = creds.ApiKey
 	}
 
 	payload := make([]batchOrderRequest, 0, len(args))
@@ -296,22 +363,7 @@ func (c *ClobClient) PostOrders(ctx context.Context, args []PostOrdersArgs, defe
 		})
 	}
 
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("polymarket: marshalling post orders request: %w", err)
-	}
-
-	headers, err := c.l2Headers("POST", EndpointPostOrders, string(body))
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Post(ctx, EndpointPostOrders, headers, payload)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
+	raw, err := c.send(ctx, apiRequest{method: http.MethodPost, path: EndpointPostOrders, body: payload, auth: authL2})
 	if err != nil {
 		return nil, err
 	}
@@ -333,12 +385,18 @@ func (c *ClobClient) PostOrders(ctx context.Context, args []PostOrdersArgs, defe
 }
 
 // CreateAndPostOrder is a convenience method that creates, signs, and posts a
-// limit order in a single call.
+// limit order in a single call. With WithBalancePreflight, the order is checked
+// against the account's balance and allowance before it is posted.
 func (c *ClobClient) CreateAndPostOrder(ctx context.Context, args OrderArgs, orderType OrderType, postOnly bool) (*OrderResponse, error) {
 	signed, err := c.CreateOrder(ctx, args)
 	if err != nil {
 		return nil, err
 	}
+	if c.balancePreflight {
+		if err := c.CheckBalanceAllowance(ctx, *signed); err != nil {
+			return nil, err
+		}
+	}
 	return c.PostOrder(ctx, *signed, orderType, postOnly)
 }
 
@@ -352,119 +410,57 @@ func (c *ClobClient) CreateAndPostMarketOrder(ctx context.Context, args MarketOr
 	if err != nil {
 		return nil, err
 	}
+	if c.balancePreflight {
+		if err := c.CheckBalanceAllowance(ctx, *signed); err != nil {
+			return nil, err
+		}
+	}
 	return c.PostOrder(ctx, *signed, orderType, postOnly)
 }
 
 // CancelOrder cancels a single order by ID.
 func (c *ClobClient) CancelOrder(ctx context.Context, orderID string) error {
-	reqBody := OrderPayload{OrderID: orderID}
-
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("polymarket: marshalling cancel request: %w", err)
-	}
-
-	headers, err := c.l2Headers("DELETE", EndpointCancelOrder, string(bodyBytes))
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.http.Delete(ctx, EndpointCancelOrder, headers, reqBody)
-	if err != nil {
-		return err
-	}
-
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, apiRequest{
+		method: http.MethodDelete,
+		path:   EndpointCancelOrder,
+		body:   OrderPayload{OrderID: orderID},
+		auth:   authL2,
+	})
 	return err
 }
 
 // CancelOrders cancels multiple orders by their IDs.
 func (c *ClobClient) CancelOrders(ctx context.Context, orderIDs []string) error {
-	reqBody := orderIDs
-
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("polymarket: marshalling cancel request: %w", err)
-	}
-
-	headers, err := c.l2Headers("DELETE", EndpointCancelOrders, string(bodyBytes))
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.http.Delete(ctx, EndpointCancelOrders, headers, reqBody)
-	if err != nil {
-		return err
-	}
-
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, apiRequest{method: http.MethodDelete, path: EndpointCancelOrders, body: orderIDs, auth: authL2})
 	return err
 }
 
 // CancelMarketOrders cancels orders by market and/or asset id.
 func (c *ClobClient) CancelMarketOrders(ctx context.Context, market, assetID string) error {
-	reqBody := OrderMarketCancelParams{
-		Market:  market,
-		AssetID: assetID,
-	}
-
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("polymarket: marshalling cancel market orders request: %w", err)
-	}
-
-	headers, err := c.l2Headers("DELETE", EndpointCancelMarketOrders, string(bodyBytes))
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.http.Delete(ctx, EndpointCancelMarketOrders, headers, reqBody)
-	if err != nil {
-		return err
-	}
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, apiRequest{
+		method: http.MethodDelete,
+		path:   EndpointCancelMarketOrders,
+		body: OrderMarketCancelParams{
+			Market:  market,
+			AssetID: assetID,
+		},
+		auth: authL2,
+	})
 	return err
 }
 
 // CancelAll cancels all open orders for the authenticated user.
 func (c *ClobClient) CancelAll(ctx context.Context) error {
-	headers, err := c.l2Headers("DELETE", EndpointCancelAll, "")
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.http.Delete(ctx, EndpointCancelAll, headers, nil)
-	if err != nil {
-		return err
-	}
-
-	_, err = transport.ParseResponse(resp)
+	_, err := c.send(ctx, apiRequest{method: http.MethodDelete, path: EndpointCancelAll, auth: authL2})
 	return err
 }
 
 // GetOrder returns a single order by ID. Requires L2 authentication.
 func (c *ClobClient) GetOrder(ctx context.Context, orderID string) (*Order, error) {
-	path := EndpointOrder + orderID
-
-	headers, err := c.l2Headers("GET", path, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, path, headers, nil)
+	order, err := call[Order](ctx, c, apiRequest{method: http.MethodGet, path: EndpointOrder + orderID, auth: authL2}, "order")
 	if err != nil {
 		return nil, err
 	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	var order Order
-	if err := json.Unmarshal(raw, &order); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing order: %w", err)
-	}
 	return &order, nil
 }
 
@@ -472,10 +468,6 @@ func (c *ClobClient) GetOrder(ctx context.Context, orderID string) (*Order, erro
 // Requires L2 authentication. The optional params filter by market or asset.
 func (c *ClobClient) GetOpenOrders(ctx context.Context, params OpenOrderParams) iter.Seq2[Order, error] {
 	return paginate[Order](ctx, func(cursor string) (PaginatedResponse[Order], error) {
-		headers, err := c.l2Headers("GET", EndpointOrders, "")
-		if err != nil {
-			return PaginatedResponse[Order]{}, err
-		}
 		query := make(map[string]string)
 		if params.Market != "" {
 			query["market"] = params.Market
@@ -489,19 +481,7 @@ func (c *ClobClient) GetOpenOrders(ctx context.Context, params OpenOrderParams)
 		if cursor != "" {
 			query["next_cursor"] = cursor
 		}
-		resp, err := c.http.Get(ctx, EndpointOrders, headers, query)
-		if err != nil {
-			return PaginatedResponse[Order]{}, err
-		}
-		body, err := transport.ParseResponse(resp)
-		if err != nil {
-			return PaginatedResponse[Order]{}, err
-		}
-		var page PaginatedResponse[Order]
-		if err := json.Unmarshal(body, &page); err != nil {
-			return PaginatedResponse[Order]{}, fmt.Errorf("polymarket: parsing orders: %w", err)
-		}
-		return page, nil
+		return call[PaginatedResponse[Order]](ctx, c, apiRequest{method: http.MethodGet, path: EndpointOrders, query: query, auth: authL2}, "orders")
 	})
 }
 
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// PoolConcurrency is how many accounts ClientPool queries at once in its
+// cross-account operations.
+const PoolConcurrency = 8
+
+// Account is one wallet managed by a ClientPool.
+type Account struct {
+	// ID keys the account in the pool, e.g. a desk-internal wallet name.
+	ID string
+	// Signer signs L1 requests and orders. Without one, Address must be set
+	// and the account can only make L2 requests.
+	Signer  *ecdsa.PrivateKey
+	Address string
+	// Funder is the proxy or Safe wallet holding the funds, if not the
+	// signer's address.
+	Funder string
+	// SignatureType is the account's order signature type; the zero value
+	// is EOA and overrides any WithSignatureType given to the pool.
+	SignatureType SignatureType
+	Creds         *ApiCreds
+}
+
+// ClientPool manages clients for many accounts. The clients share one HTTP
+// connection pool, retry and circuit breaker state, server clock and the
+// tick size, neg-risk and fee rate caches, while each keeps its own signer,
+// credentials, signature type and funder.
+type ClientPool struct {
+	base *ClobClient
+	opts []ClientOption
+
+	mu      sync.RWMutex
+	clients map[string]*ClobClient
+}
+
+// NewClientPool returns an empty pool. opts configure the shared transport
+// and defaults of every account's client; account-specific options such as
+// WithSigner belong in Account.
+func NewClientPool(opts ...ClientOption) *ClientPool {
+	return &ClientPool{
+		base:    NewClobClient(opts...),
+		opts:    opts,
+		clients: make(map[string]*ClobClient),
+	}
+}
+
+// Add creates the client for acct. It fails if the ID is empty or already in
+// the pool.
+func (p *ClientPool) Add(acct Account) (*ClobClient, error) {
+	if acct.ID == "" {
+		return nil, &ValidationError{Field: "ID", Message: "account ID is required"}
+	}
+	if acct.Signer == nil && acct.Address == "" {
+		return nil, &ValidationError{Field: "Signer", Message: "signer or address is required"}
+	}
+
+	opts := slices.Clone(p.opts)
+	if acct.Signer != nil {
+		opts = append(opts, WithSigner(acct.Signer))
+	}
+	if acct.Address != "" {
+		opts = append(opts, WithAddress(acct.Address))
+	}
+	if acct.Funder != "" {
+		opts = append(opts, WithFunderAddress(acct.Funder))
+	}
+	opts = append(opts, WithSignatureType(acct.SignatureType))
+	if acct.Creds != nil {
+		opts = append(opts, WithCreds(*acct.Creds))
+	}
+	opts = append(opts, p.sharing())
+	c := NewClobClient(opts...)
+	if err := c.ValidateFunder(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.clients[acct.ID]; ok {
+		return nil, &ValidationError{Field: "ID", Message: fmt.Sprintf("account %q already in pool", acct.ID)}
+	}
+	p.clients[acct.ID] = c
+	return c, nil
+}
+
+// sharing points a client at the pool's shared HTTP client and caches.
+func (p *ClientPool) sharing() ClientOption {
+	return func(c *ClobClient) {
+		c.http = p.base.http
+		c.meta = p.base.meta
+		c.clock = p.base.clock
+	}
+}
+
+// Remove drops an account from the pool. Its client stays usable.
+func (p *ClientPool) Remove(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, id)
+}
+
+// Client returns the client of an account.
+func (p *ClientPool) Client(id string) (*ClobClient, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	c, ok := p.clients[id]
+	return c, ok
+}
+
+// Accounts returns the IDs of the pool's accounts, sorted.
+func (p *ClientPool) Accounts() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Sorted(maps.Keys(p.clients))
+}
+
+// Public returns the pool's unauthenticated client, for market data.
+func (p *ClientPool) Public() *ClobClient {
+	return p.base
+}
+
+// CancelAll cancels every open order of every account. Errors are joined,
+// each prefixed with its account ID.
+func (p *ClientPool) CancelAll(ctx context.Context) error {
+	_, err := forEachAccount(ctx, p, func(ctx context.Context, c *ClobClient) (struct{}, error) {
+		return struct{}{}, c.CancelAll(ctx)
+	})
+	return err
+}
+
+// OpenOrders returns the open orders of every account, keyed by account ID.
+// Accounts whose orders could not be fetched are missing from the result and
+// their errors are joined, each prefixed with its account ID.
+func (p *ClientPool) OpenOrders(ctx context.Context, params OpenOrderParams) (map[string][]Order, error) {
+	return forEachAccount(ctx, p, func(ctx context.Context, c *ClobClient) ([]Order, error) {
+		var orders []Order
+		for order, err := range c.GetOpenOrders(ctx, params) {
+			if err != nil {
+				return nil, err
+			}
+			orders = append(orders, order)
+		}
+		return orders, nil
+	})
+}
+
+// PoolBalances holds the balance of each account in a pool.
+type PoolBalances struct {
+	Accounts map[string]*BalanceAllowance
+	// Total is the sum of the accounts' balances, in the API's base units.
+	Total decimal.Decimal
+}
+
+// Balances returns the balance and allowance of every account for params.
+// Like OpenOrders, it returns what it could fetch along with joined errors.
+func (p *ClientPool) Balances(ctx context.Context, params BalanceAllowanceParams) (*PoolBalances, error) {
+	accounts, err := forEachAccount(ctx, p, func(ctx context.Context, c *ClobClient) (*BalanceAllowance, error) {
+		return c.GetBalanceAllowance(ctx, params)
+	})
+	result := &PoolBalances{Accounts: accounts}
+	var errs []error
+	for _, id := range slices.Sorted(maps.Keys(accounts)) {
+		balance, perr := decimal.NewFromString(accounts[id].Balance)
+		if perr != nil {
+			errs = append(errs, fmt.Errorf("polymarket: parsing balance of account %s: %w", id, perr))
+			continue
+		}
+		result.Total = result.Total.Add(balance)
+	}
+	return result, errors.Join(append([]error{err}, errs...)...)
+}
+
+// forEachAccount runs fn for every account, at most PoolConcurrency at once.
+// It returns the results of the accounts that succeeded and the joined
+// errors of the others.
+func forEachAccount[T any](ctx context.Context, p *ClientPool, fn func(context.Context, *ClobClient) (T, error)) (map[string]T, error) {
+	p.mu.RLock()
+	clients := maps.Clone(p.clients)
+	p.mu.RUnlock()
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]T, len(clients))
+		errs    = make(map[string]error)
+	)
+	sem := make(chan struct{}, PoolConcurrency)
+	var wg sync.WaitGroup
+	for id, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			r, err := fn(ctx, c)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[id] = fmt.Errorf("account %s: %w", id, err)
+				return
+			}
+			results[id] = r
+		}()
+	}
+	wg.Wait()
+
+	var joined []error
+	for _, id := range slices.Sorted(maps.Keys(errs)) {
+		joined = append(joined, errs[id])
+	}
+	return results, errors.Join(joined...)
+}
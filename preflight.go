@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// baseUnits is the scale between human-readable USDC/share amounts and the
+// integer base units used in signed orders and balance responses.
+var baseUnits = decimal.New(1, 6)
+
+// WithBalancePreflight enables a balance/allowance check before orders are
+// posted by CreateAndPostOrder and CreateAndPostMarketOrder. The order's maker
+// amount is compared against the relevant COLLATERAL or CONDITIONAL balance
+// minus funds locked in other open orders, and against the allowance. When
+// refreshOnShortfall is true, a shortfall triggers UpdateBalanceAllowance and
+// the check is repeated once before failing.
+func WithBalancePreflight(refreshOnShortfall bool) ClientOption {
+	return func(c *ClobClient) {
+		c.balancePreflight = true
+		c.preflightRefresh = refreshOnShortfall
+	}
+}
+
+// CheckBalanceAllowance verifies that the authenticated account has enough
+// free balance and allowance to back the given signed order. BUY orders are
+// checked against collateral, SELL orders against the conditional token. Funds
+// locked in other open orders are subtracted from the balance. Returns a
+// *ValidationError describing the shortfall when the order cannot be funded.
+// Requires L2 authentication.
+func (c *ClobClient) CheckBalanceAllowance(ctx context.Context, order SignedOrder) error {
+	required, err := decimal.NewFromString(order.MakerAmount)
+	if err != nil {
+		return &ValidationError{Field: "makerAmount", Message: fmt.Sprintf("invalid maker amount %q", order.MakerAmount)}
+	}
+
+	params := BalanceAllowanceParams{SignatureType: order.SignatureType}
+	openParams := OpenOrderParams{}
+	if order.Side == Buy {
+		params.AssetType = string(AssetTypeCollateral)
+	} else {
+		params.AssetType = string(AssetTypeConditional)
+		params.TokenID = order.TokenID
+		openParams.AssetID = order.TokenID
+	}
+
+	locked, err := c.lockedInOpenOrders(ctx, order.Side, openParams)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkBalanceAllowance(ctx, params, required, locked)
+	if err == nil || !c.preflightRefresh {
+		return err
+	}
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		return err
+	}
+	if updateErr := c.UpdateBalanceAllowance(ctx, params); updateErr != nil {
+		return fmt.Errorf("polymarket: refreshing balance allowance: %w", updateErr)
+	}
+	return c.checkBalanceAllowance(ctx, params, required, locked)
+}
+
+// checkBalanceAllowance fetches the balance/allowance for params and compares
+// it against required plus locked (all in base units).
+func (c *ClobClient) checkBalanceAllowance(ctx context.Context, params BalanceAllowanceParams, required, locked decimal.Decimal) error {
+	ba, err := c.GetBalanceAllowance(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	balance, err := decimal.NewFromString(ba.Balance)
+	if err != nil {
+		return fmt.Errorf("polymarket: invalid balance from server: %s", ba.Balance)
+	}
+	available := balance.Sub(locked)
+	if available.LessThan(required) {
+		return &ValidationError{
+			Field: "balance",
+			Message: fmt.Sprintf("insufficient %s balance: order requires %s, available %s (balance %s, locked in open orders %s)",
+				params.AssetType, fromBaseUnits(required), fromBaseUnits(available), fromBaseUnits(balance), fromBaseUnits(locked)),
+		}
+	}
+
+	// Some deployments omit the scalar allowance; skip the check in that case.
+	if ba.Allowance == "" {
+		return nil
+	}
+	allowance, err := decimal.NewFromString(ba.Allowance)
+	if err != nil {
+		return fmt.Errorf("polymarket: invalid allowance from server: %s", ba.Allowance)
+	}
+	if allowance.LessThan(required) {
+		return &ValidationError{
+			Field: "allowance",
+			Message: fmt.Sprintf("insufficient %s allowance: order requires %s, allowance %s",
+				params.AssetType, fromBaseUnits(required), fromBaseUnits(allowance)),
+		}
+	}
+	return nil
+}
+
+// lockedInOpenOrders sums the unfilled maker amounts (in base units) of open
+// orders on the given side. BUY orders lock collateral (remaining size times
+// price); SELL orders lock shares (remaining size).
+func (c *ClobClient) lockedInOpenOrders(ctx context.Context, side Side, params OpenOrderParams) (decimal.Decimal, error) {
+	locked := decimal.Zero
+	for o, err := range c.GetOpenOrders(ctx, params) {
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("polymarket: fetching open orders: %w", err)
+		}
+		if Side(o.Side) != side {
+			continue
+		}
+		original, err := decimal.NewFromString(o.OriginalSize)
+		if err != nil {
+			continue
+		}
+		matched, err := decimal.NewFromString(o.SizeMatched)
+		if err != nil {
+			matched = decimal.Zero
+		}
+		remaining := original.Sub(matched)
+		if remaining.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+		if side == Buy {
+			price, err := decimal.NewFromString(o.Price)
+			if err != nil {
+				continue
+			}
+			remaining = remaining.Mul(price)
+		}
+		locked = locked.Add(remaining.Mul(baseUnits))
+	}
+	return locked, nil
+}
+
+func fromBaseUnits(d decimal.Decimal) string {
+	return d.Div(baseUnits).String()
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// hedgeWindow is how many recent latencies per endpoint the hedge delay
+	// is computed from.
+	hedgeWindow = 256
+	// hedgeMinSamples is how many latencies an endpoint needs before its
+	// percentile replaces the initial hedge delay.
+	hedgeMinSamples = 20
+)
+
+// DefaultHedgeDelay is the initial hedge delay used when WithHedging is given
+// a non-positive one.
+const DefaultHedgeDelay = 50 * time.Millisecond
+
+// WithHedging enables hedged requests for GetOrderBook, GetMidpoint and
+// GetPrice. If a request has not returned after the given percentile of the
+// endpoint's recent latencies (e.g. 0.95), an identical second request is
+// sent and whichever succeeds first wins; the other is cancelled. Until an
+// endpoint has enough samples, initialDelay is used; a non-positive one is
+// replaced by DefaultHedgeDelay. Each request keeps the transport's retries,
+// so hedging adds at most one extra request per call.
+func WithHedging(percentile float64, initialDelay time.Duration) ClientOption {
+	return func(c *ClobClient) {
+		if initialDelay <= 0 {
+			initialDelay = DefaultHedgeDelay
+		}
+		c.hedge = &hedger{
+			percentile: min(max(percentile, 0), 1),
+			initial:    initialDelay,
+			latencies:  make(map[string]*latencyWindow),
+		}
+	}
+}
+
+// WithReadFanOut splits GetOrderBooks, GetMidpoints, GetPrices, GetSpreads
+// and GetLastTradesPrices into requests of at most chunkSize tokens, with up
+// to parallelism of them in flight, and merges the results. A chunkSize of 0
+// (the default) sends each call as a single request.
+func WithReadFanOut(chunkSize, parallelism int) ClientOption {
+	return func(c *ClobClient) {
+		c.fanOutChunk = max(chunkSize, 0)
+		c.fanOutParallel = max(parallelism, 1)
+	}
+}
+
+// hedger tracks per-endpoint latencies and derives hedge delays from them.
+type hedger struct {
+	percentile float64
+	initial    time.Duration
+
+	mu        sync.Mutex
+	latencies map[string]*latencyWindow
+}
+
+// latencyWindow is a ring buffer of an endpoint's recent latencies.
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+func (h *hedger) observe(path string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	w := h.latencies[path]
+	if w == nil {
+		w = &latencyWindow{}
+		h.latencies[path] = w
+	}
+	if len(w.samples) < hedgeWindow {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % hedgeWindow
+}
+
+func (h *hedger) delay(path string) time.Duration {
+	h.mu.Lock()
+	w := h.latencies[path]
+	if w == nil || len(w.samples) < hedgeMinSamples {
+		h.mu.Unlock()
+		return h.initial
+	}
+	sorted := slices.Clone(w.samples)
+	h.mu.Unlock()
+	slices.Sort(sorted)
+	return sorted[int(h.percentile*float64(len(sorted)-1))]
+}
+
+// hedgedGet is getJSON with hedging when WithHedging is set.
+func (c *ClobClient) hedgedGet(ctx context.Context, path string, query map[string]string) (json.RawMessage, error) {
+	if c.hedge == nil {
+		return c.getJSON(ctx, path, query)
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // cancels the losing request
+
+	type result struct {
+		raw json.RawMessage
+		err error
+	}
+	results := make(chan result, 2)
+	attempt := func(primary bool) {
+		start := time.Now()
+		raw, err := c.getJSON(ctx, path, query)
+		// Only the primary request is sampled, and also when it lost to the
+		// hedge, its elapsed time then being a lower bound. Sampling winners
+		// alone would drag the percentile, and so the hedge delay, down.
+		lost := ctx.Err() != nil && parent.Err() == nil
+		if primary && (err == nil || lost) {
+			c.hedge.observe(path, time.Since(start))
+		}
+		results <- result{raw, err}
+	}
+
+	go attempt(true)
+	timer := time.NewTimer(c.hedge.delay(path))
+	defer timer.Stop()
+	inFlight := 1
+	for {
+		select {
+		case <-timer.C:
+			go attempt(false)
+			inFlight++
+		case r := <-results:
+			inFlight--
+			// A failed request is only final once no other can succeed.
+			if r.err == nil || inFlight == 0 {
+				return r.raw, r.err
+			}
+		}
+	}
+}
+
+// fanOut splits items into chunks per WithReadFanOut and fetches them
+// concurrently, returning the results in chunk order. The first error
+// cancels the remaining chunks and is returned.
+func fanOut[P, R any](ctx context.Context, c *ClobClient, items []P, fetch func(context.Context, []P) (R, error)) ([]R, error) {
+	size := c.fanOutChunk
+	if size == 0 || len(items) <= size {
+		r, err := fetch(ctx, items)
+		if err != nil {
+			return nil, err
+		}
+		return []R{r}, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	results := make([]R, (len(items)+size-1)/size)
+	sem := make(chan struct{}, c.fanOutParallel)
+	var wg sync.WaitGroup
+	for i := range results {
+		chunk := items[i*size : min((i+1)*size, len(items))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+			r, err := fetch(ctx, chunk)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = r
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// batchRead posts token batches to a batch market data endpoint, fanning out
+// per WithReadFanOut, and merges the per-token results.
+func batchRead[V any](ctx context.Context, c *ClobClient, path string, params []BookParams, what string) (map[string]V, error) {
+	parts, err := fanOut(ctx, c, params, func(ctx context.Context, chunk []BookParams) (map[string]V, error) {
+		return call[map[string]V](ctx, c, apiRequest{method: http.MethodPost, path: path, body: chunk}, what)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]V)
+	for _, part := range parts {
+		maps.Copy(result, part)
+	}
+	return result, nil
+}
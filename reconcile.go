@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/lubluniky/clob-client-go/internal/orderbuilder"
+	"github.com/lubluniky/clob-client-go/internal/transport"
+)
+
+// defaultRetryPolicies marks the endpoints whose requests are not idempotent:
+// they are retried only when they provably never reached the server. Cancels
+// are idempotent and keep the default policy.
+func defaultRetryPolicies() []transport.Option {
+	var opts []transport.Option
+	for _, path := range []string{
+		EndpointPostOrder,
+		EndpointPostOrders,
+		EndpointCreateApiKey,
+		EndpointCreateReadonlyApiKey,
+		EndpointCreateBuilderApiKey,
+		EndpointRfqRequest,
+		EndpointRfqQuote,
+		EndpointRfqRequestAccept,
+		EndpointRfqQuoteApprove,
+	} {
+		opts = append(opts, transport.WithRetryPolicy(http.MethodPost, path, transport.RetryUnsent))
+	}
+	return opts
+}
+
+// WithOrderResends sets how many times PostOrder and PostOrders resend an
+// order whose post had an unknown outcome and that the exchange does not know
+// when looked up afterwards. The resend carries the same signed order, so the
+// exchange rejects it as a duplicate if the first post did land after all.
+// Default 1; 0 only looks the order up and returns an error matching
+// ErrOutcomeUnknown if it is not found.
+func WithOrderResends(n int) ClientOption {
+	return func(c *ClobClient) {
+		c.orderResends = max(n, 0)
+	}
+}
+
+// reconcileTimeout bounds the lookups and resends that settle a post whose
+// outcome is unknown.
+const reconcileTimeout = 10 * time.Second
+
+// reconcileContext returns the context an unknown outcome is settled under.
+// It is detached from ctx, since ctx running out is the most common reason
+// the outcome is unknown, and bounded by reconcileTimeout instead.
+func reconcileContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), reconcileTimeout)
+}
+
+// OrderHash returns the ID the exchange assigns to a signed order: its EIP-712
+// hash, computed against the exchange contract for the order's market.
+func (c *ClobClient) OrderHash(ctx context.Context, order SignedOrder) (string, error) {
+	negRisk, err := c.GetNegRisk(ctx, order.TokenID)
+	if err != nil {
+		return "", err
+	}
+	hash, err := orderbuilder.HashOrder(c.chainID, orderbuilder.OrderData{
+		Maker:         common.HexToAddress(order.Maker),
+		Taker:         common.HexToAddress(order.Taker),
+		TokenID:       order.TokenID,
+		MakerAmount:   order.MakerAmount,
+		TakerAmount:   order.TakerAmount,
+		Side:          sideToInt(order.Side),
+		FeeRateBps:    order.FeeRateBps,
+		Nonce:         order.Nonce,
+		Signer:        common.HexToAddress(order.Signer),
+		Expiration:    order.Expiration,
+		SignatureType: int(order.SignatureType),
+		Salt:          order.Salt,
+	}, negRisk)
+	if err != nil {
+		return "", fmt.Errorf("polymarket: hashing order: %w", err)
+	}
+	return hash.Hex(), nil
+}
+
+// lookupOrder returns the exchange's record of order, or nil if it has none.
+func (c *ClobClient) lookupOrder(ctx context.Context, order SignedOrder) (string, *Order, error) {
+	hash, err := c.OrderHash(ctx, order)
+	if err != nil {
+		return "", nil, err
+	}
+	found, err := c.GetOrder(ctx, hash)
+	if errors.Is(err, ErrNotFound) || (err == nil && found.ID == "") {
+		return hash, nil, nil
+	}
+	if err != nil {
+		return hash, nil, err
+	}
+	return hash, found, nil
+}
+
+// reconciled reports an order found on the exchange as a successful post.
+func reconciled(found *Order) OrderResponse {
+	resp := OrderResponse{ID: found.ID, Success: true, Status: found.Status}
+	resp.normalizeIDs()
+	return resp
+}
+
+// reconcileOrder settles a post whose outcome is unknown: it looks the order
+// up by hash and calls resend only if the exchange has no record of it.
+func (c *ClobClient) reconcileOrder(ctx context.Context, order SignedOrder, cause error, resend func() (*OrderResponse, error)) (*OrderResponse, error) {
+	for attempt := 0; ; attempt++ {
+		hash, found, err := c.lookupOrder(ctx, order)
+		if err != nil {
+			return nil, fmt.Errorf("polymarket: reconciling order: %w (lookup: %v)", cause, err)
+		}
+		if found != nil {
+			resp := reconciled(found)
+			return &resp, nil
+		}
+		if attempt >= c.orderResends {
+			return nil, cause
+		}
+
+		resp, err := resend()
+		if errors.Is(err, ErrDuplicateOrder) {
+			// The original post landed between the lookup and the resend.
+			resp := OrderResponse{ID: hash, Success: true}
+			resp.normalizeIDs()
+			return &resp, nil
+		}
+		if !errors.Is(err, ErrOutcomeUnknown) {
+			return resp, err
+		}
+		cause = err
+	}
+}
+
+// reconcileOrders is reconcileOrder for a batch. Orders the exchange knows are
+// reported from its records; the rest are resent together, keeping results in
+// the order of args. As in reconcileOrder, a resend rejected as a duplicate
+// counts as accepted. Orders left unsettled get their own entry in errs, so a
+// later failure never discards the orders already found; err is the first of
+// those errors, or nil if every order was settled.
+func (c *ClobClient) reconcileOrders(ctx context.Context, args []PostOrdersArgs, cause error, resend func([]PostOrdersArgs) ([]OrderResponse, error)) (results []OrderResponse, errs []error, err error) {
+	results = make([]OrderResponse, len(args))
+	errs = make([]error, len(args))
+	hashes := make([]string, len(args))
+	pending := make([]int, len(args))
+	for i := range pending {
+		pending[i] = i
+	}
+	for attempt := 0; ; attempt++ {
+		var missing []int
+		for _, i := range pending {
+			hash, found, lerr := c.lookupOrder(ctx, args[i].Order)
+			hashes[i] = hash
+			switch {
+			case lerr != nil:
+				// Still unknown, and resending could double the order.
+				errs[i] = fmt.Errorf("polymarket: reconciling order: %w (lookup: %v)", cause, lerr)
+			case found != nil:
+				results[i] = reconciled(found)
+			default:
+				missing = append(missing, i)
+			}
+		}
+		if len(missing) == 0 {
+			break
+		}
+		if attempt >= c.orderResends {
+			for _, i := range missing {
+				errs[i] = cause
+			}
+			break
+		}
+
+		batch := make([]PostOrdersArgs, len(missing))
+		for j, i := range missing {
+			batch[j] = args[i]
+		}
+		resps, rerr := resend(batch)
+		if errors.Is(rerr, ErrOutcomeUnknown) {
+			pending, cause = missing, rerr
+			continue
+		}
+		for j, i := range missing {
+			switch {
+			case errors.Is(rerr, ErrDuplicateOrder),
+				rerr == nil && j < len(resps) && errors.Is(orderRejection(resps[j]), ErrDuplicateOrder):
+				// The original post landed between the lookup and the resend.
+				results[i] = OrderResponse{ID: hashes[i], Success: true}
+				results[i].normalizeIDs()
+			case rerr != nil:
+				errs[i] = rerr
+			case j < len(resps):
+				results[i] = resps[j]
+			}
+		}
+		break
+	}
+	for _, e := range errs {
+		if e != nil {
+			return results, errs, e
+		}
+	}
+	return results, errs, nil
+}
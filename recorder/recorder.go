@@ -0,0 +1,256 @@
+// Package recorder captures market data from the WebSocket client into
+// gzip-compressed, timestamped JSONL event logs. The logs are the input for
+// the backtest package, which needs full book and trade resolution rather
+// than the candles returned by GetPricesHistory.
+package recorder
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+// FlushInterval is how often Run flushes the compressed stream, bounding how
+// much data is lost if the process dies without calling Close.
+const FlushInterval = time.Second
+
+// Event is one recorded market data event. Exactly one of Book, PriceChange
+// and LastTrade is set, matching Type.
+type Event struct {
+	// Time is the local receive time; the exchange timestamp stays in the
+	// payload.
+	Time        time.Time          `json:"t"`
+	Type        string             `json:"type"`
+	Book        *ws.BookUpdate     `json:"book,omitempty"`
+	PriceChange *ws.PriceChange    `json:"price_change,omitempty"`
+	LastTrade   *ws.LastTradePrice `json:"last_trade,omitempty"`
+}
+
+// Recorder writes events to a gzip-compressed JSONL stream. It is safe for
+// concurrent use.
+type Recorder struct {
+	client *ws.Client
+	now    func() time.Time
+
+	mu     sync.Mutex
+	gz     *gzip.Writer
+	enc    *json.Encoder
+	closer io.Closer
+	count  int64
+	err    error
+}
+
+// New returns a recorder that writes to w. Close flushes the compressed stream
+// but does not close w.
+func New(client *ws.Client, w io.Writer) *Recorder {
+	gz := gzip.NewWriter(w)
+	return &Recorder{
+		client: client,
+		now:    time.Now,
+		gz:     gz,
+		enc:    json.NewEncoder(gz),
+	}
+}
+
+// Create returns a recorder that writes to a new file at path, which is closed
+// by Close. By convention the file name ends in ".jsonl.gz".
+func Create(client *ws.Client, path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: creating log: %w", err)
+	}
+	r := New(client, f)
+	r.closer = f
+	return r, nil
+}
+
+// Run subscribes to order book, price change and last trade events for the
+// given assets and records them until ctx is canceled or every stream closes.
+// It returns the first write error, or nil on a clean shutdown.
+func (r *Recorder) Run(ctx context.Context, assetIDs ...string) error {
+	if len(assetIDs) == 0 {
+		return errors.New("recorder: at least one asset ID is required")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	books := r.client.SubscribeOrderBook(ctx, assetIDs...)
+	prices := r.client.SubscribePrices(ctx, assetIDs...)
+	trades := r.client.SubscribeLastTradePrice(ctx, assetIDs...)
+
+	flush := time.NewTicker(FlushInterval)
+	defer flush.Stop()
+
+	for books != nil || prices != nil || trades != nil {
+		var err error
+		select {
+		case <-ctx.Done():
+			return r.Flush()
+		case <-flush.C:
+			err = r.Flush()
+		case b, ok := <-books:
+			if !ok {
+				books = nil
+				continue
+			}
+			err = r.Write(Event{Type: ws.EventBook, Book: &b})
+		case p, ok := <-prices:
+			if !ok {
+				prices = nil
+				continue
+			}
+			err = r.Write(Event{Type: ws.EventPriceChange, PriceChange: &p})
+		case t, ok := <-trades:
+			if !ok {
+				trades = nil
+				continue
+			}
+			err = r.Write(Event{Type: ws.EventLastTradePrice, LastTrade: &t})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return r.Flush()
+}
+
+// Write appends one event, stamping Time if it is zero. After the first write
+// error every later call returns that error.
+func (r *Recorder) Write(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = r.now()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.enc.Encode(e); err != nil {
+		r.err = fmt.Errorf("recorder: writing event: %w", err)
+		return r.err
+	}
+	r.count++
+	return nil
+}
+
+// Count returns the number of events written so far.
+func (r *Recorder) Count() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Flush writes buffered compressed data to the underlying writer.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.gz.Flush(); err != nil {
+		r.err = fmt.Errorf("recorder: flushing log: %w", err)
+	}
+	return r.err
+}
+
+// Close finishes the gzip stream and closes the file if the recorder owns it.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := r.gz.Close()
+	if r.closer != nil {
+		if cerr := r.closer.Close(); err == nil {
+			err = cerr
+		}
+		r.closer = nil
+	}
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("recorder: closing log: %w", err)
+	}
+	return r.err
+}
+
+// ---------------------------------------------------------------------------
+// Reading logs
+// ---------------------------------------------------------------------------
+
+// Reader decodes events from a log written by Recorder.
+type Reader struct {
+	gz     *gzip.Reader
+	dec    *json.Decoder
+	closer io.Closer
+}
+
+// NewReader reads a compressed log from r.
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: opening log: %w", err)
+	}
+	return &Reader{gz: gz, dec: json.NewDecoder(gz)}, nil
+}
+
+// Open reads the compressed log at path.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: opening log: %w", err)
+	}
+	r, err := NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r.closer = f
+	return r, nil
+}
+
+// Next returns the next event, or io.EOF at the end of the log. A log cut
+// short by a crash ends with io.ErrUnexpectedEOF after the last complete
+// event.
+func (r *Reader) Next() (Event, error) {
+	var e Event
+	if err := r.dec.Decode(&e); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Event{}, io.EOF
+		}
+		return Event{}, fmt.Errorf("recorder: reading event: %w", err)
+	}
+	return e, nil
+}
+
+// Events iterates over the remaining events. Iteration stops after the first
+// error; io.EOF is not reported.
+func (r *Reader) Events() iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		for {
+			e, err := r.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(e, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Close releases the reader and closes the file if it was opened by Open.
+func (r *Reader) Close() error {
+	err := r.gz.Close()
+	if r.closer != nil {
+		if cerr := r.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
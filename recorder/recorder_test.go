@@ -0,0 +1,71 @@
+package recorder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+func TestWriteAndReadRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(nil, &buf)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return at }
+
+	events := []Event{
+		{Type: ws.EventBook, Book: &ws.BookUpdate{AssetID: "1", Market: "m", Bids: []ws.BookLevel{{Price: "0.4", Size: "10"}}}},
+		{Type: ws.EventPriceChange, PriceChange: &ws.PriceChange{Market: "m"}},
+		{Time: at.Add(time.Second), Type: ws.EventLastTradePrice, LastTrade: &ws.LastTradePrice{AssetID: "1", Price: "0.41"}},
+	}
+	for _, e := range events {
+		if err := r.Write(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if r.Count() != 3 {
+		t.Fatalf("count = %d", r.Count())
+	}
+
+	// The log is plain gzip-compressed JSONL.
+	gz, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	raw, _ := io.ReadAll(gz)
+	if lines := strings.Count(string(raw), "\n"); lines != 3 {
+		t.Fatalf("expected 3 JSONL lines, got %d:\n%s", lines, raw)
+	}
+
+	rd, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("reader: %v", err)
+	}
+	defer rd.Close()
+	var got []Event
+	for e, err := range rd.Events() {
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 3 {
+		t.Fatalf("read %d events", len(got))
+	}
+	if !got[0].Time.Equal(at) || got[0].Book.Bids[0].Price != "0.4" {
+		t.Fatalf("unexpected book event %+v", got[0])
+	}
+	if got[1].Type != ws.EventPriceChange || got[1].PriceChange.Market != "m" {
+		t.Fatalf("unexpected price change event %+v", got[1])
+	}
+	if !got[2].Time.Equal(at.Add(time.Second)) || got[2].LastTrade.Price != "0.41" {
+		t.Fatalf("unexpected last trade event %+v", got[2])
+	}
+}
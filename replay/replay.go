@@ -0,0 +1,494 @@
+// Package replay records HTTP exchanges and WebSocket frames to a JSONL file
+// and serves them back without network access. Recordings are useful for
+// reproducing production incidents locally and for building regression
+// fixtures from real sessions.
+//
+// Secret POLY_* auth headers, the credentials in API key responses, the owner
+// key of order posts and the auth block of WebSocket subscription requests
+// are redacted before anything is written.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lubluniky/clob-client-go/observe"
+)
+
+// Entry kinds.
+const (
+	KindHTTP = "http"
+	KindWS   = "ws"
+)
+
+// WebSocket frame directions.
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+// Entry is one line of a recording.
+type Entry struct {
+	Kind string    `json:"kind"`
+	Time time.Time `json:"time"`
+
+	// HTTP exchange fields.
+	Method         string      `json:"method,omitempty"`
+	Path           string      `json:"path,omitempty"`
+	Query          string      `json:"query,omitempty"`
+	RequestHeader  http.Header `json:"request_header,omitempty"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	Status         int         `json:"status,omitempty"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+	DurationMs     int64       `json:"duration_ms,omitempty"`
+
+	// WebSocket frame fields.
+	URL       string `json:"url,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	Data      string `json:"data,omitempty"`
+}
+
+// ---------------------------------------------------------------------------
+// Recorder
+// ---------------------------------------------------------------------------
+
+// Recorder appends entries to a JSONL sink. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+	err    error
+}
+
+// NewRecorder creates (or truncates) the file at path and records into it.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: creating recording: %w", err)
+	}
+	r := NewRecorderWriter(f)
+	r.closer = f
+	return r, nil
+}
+
+// NewRecorderWriter records into w. Close does not close w.
+func NewRecorderWriter(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// Record writes one entry. The first write error is retained and returned by
+// Err and Close; later entries are dropped.
+func (r *Recorder) Record(e Entry) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	r.err = r.enc.Encode(e)
+}
+
+// RecordWSFrame records one WebSocket frame. Outbound subscription requests
+// have their auth credentials redacted.
+func (r *Recorder) RecordWSFrame(wsURL, direction string, data []byte) {
+	if direction == DirectionOut {
+		data = redactWSAuth(data)
+	}
+	r.Record(Entry{Kind: KindWS, URL: wsURL, Direction: direction, Data: string(data)})
+}
+
+// Err returns the first write error, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Close closes the underlying file when the recorder owns it.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.closer = nil
+	}
+	return r.err
+}
+
+// RoundTripper wraps next (http.DefaultTransport if nil) so that every
+// exchange passing through it is recorded.
+func (r *Recorder) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recordingTransport{rec: r, next: next}
+}
+
+type recordingTransport struct {
+	rec  *Recorder
+	next http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("replay: reading request body: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("replay: reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.rec.Record(Entry{
+		Kind:           KindHTTP,
+		Time:           start,
+		Method:         req.Method,
+		Path:           req.URL.Path,
+		Query:          req.URL.RawQuery,
+		RequestHeader:  observe.RedactHeaders(req.Header),
+		RequestBody:    string(redactOwner(reqBody)),
+		Status:         resp.StatusCode,
+		ResponseHeader: resp.Header.Clone(),
+		ResponseBody:   string(redactCreds(respBody)),
+		DurationMs:     time.Since(start).Milliseconds(),
+	})
+	return resp, nil
+}
+
+// redactWSAuth masks the auth block of a subscription request. Frames that are
+// not JSON objects with an auth field are returned unchanged.
+func redactWSAuth(data []byte) []byte {
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return data
+	}
+	auth, ok := msg["auth"]
+	if !ok || string(auth) == "null" {
+		return data
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(auth, &fields); err != nil {
+		return data
+	}
+	redacted, _ := json.Marshal(observe.Redacted)
+	for k := range fields {
+		fields[k] = redacted
+	}
+	msg["auth"], _ = json.Marshal(fields)
+	out, err := json.Marshal(msg)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// credFields are the response fields that carry API credentials.
+var credFields = []string{"apiKey", "secret", "passphrase"}
+
+// redactedSecret replaces API secrets. It is valid base64, so a replayed
+// session that bootstraps its credentials can still sign requests.
+var redactedSecret = base64.URLEncoding.EncodeToString([]byte(observe.Redacted))
+
+// redactCreds masks API credentials in a response body that is a JSON object,
+// as returned when creating or deriving an API key. Other bodies are returned
+// unchanged.
+func redactCreds(data []byte) []byte {
+	if !bytes.Contains(data, []byte(`"secret"`)) && !bytes.Contains(data, []byte(`"passphrase"`)) {
+		return data
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return data
+	}
+	for _, k := range credFields {
+		if _, ok := msg[k]; !ok {
+			continue
+		}
+		placeholder := observe.Redacted
+		if k == "secret" {
+			placeholder = redactedSecret
+		}
+		msg[k], _ = json.Marshal(placeholder)
+	}
+	out, err := json.Marshal(msg)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Replayer
+// ---------------------------------------------------------------------------
+
+// Replayer serves a recording back. HTTP requests are matched on method, path,
+// normalized query and body; headers (including the time-dependent HMAC
+// signature headers) are ignored, and so are the owner of order posts and the
+// salt and signature of signed orders, which differ between runs. Repeated identical requests are served in
+// recorded order, and the last response is reused once they run out.
+//
+// WebSocket frames are served per URL path, so a recording made against one
+// endpoint can be replayed against another.
+type Replayer struct {
+	mu   sync.Mutex
+	http map[string][]Entry
+	used map[string]int
+	ws   map[string][][]byte
+	wsAt map[string]int
+}
+
+// Load reads a recording from the file at path.
+func Load(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: opening recording: %w", err)
+	}
+	defer f.Close()
+	return LoadReader(f)
+}
+
+// LoadReader reads a recording from r.
+func LoadReader(r io.Reader) (*Replayer, error) {
+	rp := &Replayer{
+		http: make(map[string][]Entry),
+		used: make(map[string]int),
+		ws:   make(map[string][][]byte),
+		wsAt: make(map[string]int),
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("replay: parsing line %d: %w", line, err)
+		}
+		switch e.Kind {
+		case KindHTTP:
+			key := requestKey(e.Method, e.Path, e.Query, []byte(e.RequestBody))
+			rp.http[key] = append(rp.http[key], e)
+		case KindWS:
+			if e.Direction != DirectionIn {
+				continue
+			}
+			p := wsPath(e.URL)
+			rp.ws[p] = append(rp.ws[p], []byte(e.Data))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("replay: reading recording: %w", err)
+	}
+	return rp, nil
+}
+
+// RoundTripper returns an http.RoundTripper that answers from the recording.
+// Unmatched requests fail with an error naming the request.
+func (rp *Replayer) RoundTripper() http.RoundTripper {
+	return replayTransport{rp: rp}
+}
+
+type replayTransport struct {
+	rp *Replayer
+}
+
+func (t replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("replay: reading request body: %w", err)
+		}
+	}
+
+	key := requestKey(req.Method, req.URL.Path, req.URL.RawQuery, body)
+	e, ok := t.rp.next(key)
+	if !ok {
+		return nil, fmt.Errorf("replay: no recorded response for %s %s?%s", req.Method, req.URL.Path, req.URL.RawQuery)
+	}
+
+	header := e.ResponseHeader.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(e.ResponseBody)),
+		ContentLength: int64(len(e.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+func (rp *Replayer) next(key string) (Entry, bool) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	entries := rp.http[key]
+	if len(entries) == 0 {
+		return Entry{}, false
+	}
+	i := rp.used[key]
+	if i >= len(entries) {
+		i = len(entries) - 1
+	} else {
+		rp.used[key] = i + 1
+	}
+	return entries[i], true
+}
+
+// NextWSFrame returns the next recorded inbound frame for the URL's path. It
+// returns false once the recording for that path is exhausted; frames are not
+// replayed again after a reconnect.
+func (rp *Replayer) NextWSFrame(wsURL string) ([]byte, bool) {
+	p := wsPath(wsURL)
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	i := rp.wsAt[p]
+	if i >= len(rp.ws[p]) {
+		return nil, false
+	}
+	rp.wsAt[p] = i + 1
+	return rp.ws[p][i], true
+}
+
+// requestKey identifies a request independent of headers and query order.
+func requestKey(method, path, rawQuery string, body []byte) string {
+	return method + " " + path + "?" + normalizeQuery(rawQuery) + "\n" + normalizeBody(body)
+}
+
+// normalizeBody strips what differs between runs from an order post: the
+// salt and signature of each signed order, and the owner, which is the API
+// key. Other bodies are only trimmed.
+func normalizeBody(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if out, ok := editOrderPosts(body, func(post, order map[string]json.RawMessage) {
+		delete(post, "owner")
+		delete(order, "salt")
+		delete(order, "signature")
+	}); ok {
+		return string(out)
+	}
+	return string(body)
+}
+
+// redactOwner masks the API key that order posts carry as their owner.
+func redactOwner(body []byte) []byte {
+	redacted, _ := json.Marshal(observe.Redacted)
+	if out, ok := editOrderPosts(body, func(post, _ map[string]json.RawMessage) {
+		if _, ok := post["owner"]; ok {
+			post["owner"] = redacted
+		}
+	}); ok {
+		return out
+	}
+	return body
+}
+
+// editOrderPosts applies edit to every post in body, a JSON object or array
+// of objects with an "order" field as sent to the order endpoints, and
+// returns the re-encoded body. It reports false for any other body.
+func editOrderPosts(body []byte, edit func(post, order map[string]json.RawMessage)) ([]byte, bool) {
+	body = bytes.TrimSpace(body)
+	if !bytes.Contains(body, []byte(`"order"`)) {
+		return nil, false
+	}
+	single := body[0] == '{'
+	var posts []map[string]json.RawMessage
+	if single {
+		posts = make([]map[string]json.RawMessage, 1)
+		if err := json.Unmarshal(body, &posts[0]); err != nil {
+			return nil, false
+		}
+	} else if err := json.Unmarshal(body, &posts); err != nil {
+		return nil, false
+	}
+	for _, post := range posts {
+		var order map[string]json.RawMessage
+		if err := json.Unmarshal(post["order"], &order); err != nil {
+			return nil, false
+		}
+		edit(post, order)
+		post["order"], _ = json.Marshal(order)
+	}
+	var v any = posts
+	if single {
+		v = posts[0]
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func normalizeQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		vals := append([]string(nil), values[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(k) + "=" + url.QueryEscape(v))
+		}
+	}
+	return b.String()
+}
+
+func wsPath(wsURL string) string {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return wsURL
+	}
+	return u.Path
+}
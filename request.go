@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/lubluniky/clob-client-go/internal/transport"
+)
+
+// Encoder serializes request bodies to JSON.
+type Encoder func(v any) ([]byte, error)
+
+// WithEncoder replaces json.Marshal for request bodies, for example with a
+// faster JSON library. The encoded bytes are exactly what is signed and sent.
+func WithEncoder(enc Encoder) ClientOption {
+	return func(c *ClobClient) {
+		if enc != nil {
+			c.encoder = enc
+		}
+	}
+}
+
+// RawBody is a request body that is already serialized. It bypasses the
+// encoder and is signed and sent byte for byte.
+type RawBody []byte
+
+// authLevel selects how a request is authenticated.
+type authLevel int
+
+const (
+	authNone authLevel = iota // L0: public
+	authL1                    // EIP-712 wallet signature
+	authL2                    // HMAC over method, path and body
+)
+
+// apiRequest describes one API call. send serializes the body once, signs
+// those bytes and sends the same bytes, so the L2 signature always covers
+// what is on the wire.
+type apiRequest struct {
+	method string
+	path   string
+	query  map[string]string
+	// rawQuery is appended to the URL verbatim, for lists the server expects
+	// unescaped, followed by the encoded query if both are set. Like query,
+	// it is not signed.
+	rawQuery string
+	body     any
+	auth     authLevel
+	nonce    int       // L1 only
+	creds    *ApiCreds // L2 only; overrides the client's credentials
+}
+
+// encode serializes a request body; nil means no body.
+func (c *ClobClient) encode(body any) ([]byte, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case RawBody:
+		return b, nil
+	case json.RawMessage:
+		return b, nil
+	}
+	data, err := c.encoder(body)
+	if err != nil {
+		return nil, fmt.Errorf("polymarket: marshalling request body: %w", err)
+	}
+	return data, nil
+}
+
+// send performs r and returns the body of a 2xx response.
+func (c *ClobClient) send(ctx context.Context, r apiRequest) ([]byte, error) {
+	body, err := c.encode(r.body)
+	if err != nil {
+		return nil, err
+	}
+
+	var headers http.Header
+	switch r.auth {
+	case authL1:
+		headers, err = c.l1Headers(r.nonce)
+	case authL2:
+		headers, err = c.l2Headers(r.creds, r.method, r.path, string(body))
+	default:
+		headers = c.l0Headers()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	path, query := r.path, r.query
+	if r.rawQuery != "" {
+		// The transport re-encodes the whole query when it adds its own, which
+		// would escape rawQuery, so query is appended here instead.
+		raw := r.rawQuery
+		if len(query) > 0 {
+			q := make(url.Values, len(query))
+			for k, v := range query {
+				q.Set(k, v)
+			}
+			raw += "&" + q.Encode()
+		}
+		path, query = path+"?"+raw, nil
+	}
+	resp, err := c.http.Send(ctx, r.method, path, headers, query, body)
+	if err != nil {
+		return nil, err
+	}
+	return transport.ParseResponse(resp)
+}
+
+// call performs r and decodes the response into T; what names the response
+// in errors.
+func call[T any](ctx context.Context, c *ClobClient, r apiRequest, what string) (T, error) {
+	var result T
+	raw, err := c.send(ctx, r)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return result, fmt.Errorf("polymarket: parsing %s: %w", what, err)
+	}
+	return result, nil
+}
+
+// DoAuthenticated sends an L2-authenticated request for endpoints without a
+// typed method and returns the raw body of a 2xx response. body is encoded
+// with the client's encoder, sent as is if it is a RawBody, or omitted if nil.
+func (c *ClobClient) DoAuthenticated(ctx context.Context, method, path string, query map[string]string, body any) (json.RawMessage, error) {
+	raw, err := c.send(ctx, apiRequest{method: method, path: path, query: query, body: body, auth: authL2})
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(raw), nil
+}
@@ -3,30 +3,17 @@ package client
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-
-	"github.com/lubluniky/clob-client-go/internal/transport"
+	"net/http"
 )
 
 // GetEarningsForDay returns the authenticated user's earnings for the current
 // day. The response is returned as raw JSON since its structure may vary.
 // Requires L2 authentication.
 func (c *ClobClient) GetEarningsForDay(ctx context.Context) (json.RawMessage, error) {
-	headers, err := c.l2Headers("GET", EndpointRewardsUser, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointRewardsUser, headers, nil)
+	raw, err := c.send(ctx, apiRequest{method: http.MethodGet, path: EndpointRewardsUser, auth: authL2})
 	if err != nil {
 		return nil, err
 	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
 	return json.RawMessage(raw), nil
 }
 
@@ -39,21 +26,10 @@ func (c *ClobClient) GetEarningsForUserForDay(ctx context.Context) (json.RawMess
 // The response is returned as raw JSON since its structure may vary.
 // Requires L2 authentication.
 func (c *ClobClient) GetTotalEarnings(ctx context.Context) (json.RawMessage, error) {
-	headers, err := c.l2Headers("GET", EndpointRewardsUserTotal, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointRewardsUserTotal, headers, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
+	raw, err := c.send(ctx, apiRequest{method: http.MethodGet, path: EndpointRewardsUserTotal, auth: authL2})
 	if err != nil {
 		return nil, err
 	}
-
 	return json.RawMessage(raw), nil
 }
 
@@ -66,43 +42,18 @@ func (c *ClobClient) GetTotalEarningsForUserForDay(ctx context.Context) (json.Ra
 // the authenticated user is participating in.
 // Requires L2 authentication.
 func (c *ClobClient) GetRewardPercentages(ctx context.Context) ([]RewardPercentage, error) {
-	headers, err := c.l2Headers("GET", EndpointRewardsUserPercentages, "")
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.http.Get(ctx, EndpointRewardsUserPercentages, headers, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	var percentages []RewardPercentage
-	if err := json.Unmarshal(raw, &percentages); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing reward percentages: %w", err)
-	}
-	return percentages, nil
+	return call[[]RewardPercentage](ctx, c, apiRequest{
+		method: http.MethodGet,
+		path:   EndpointRewardsUserPercentages,
+		auth:   authL2,
+	}, "reward percentages")
 }
 
 // GetCurrentRewardsMarkets returns markets currently eligible for rewards.
 // The response is returned as raw JSON since its structure may vary.
 // This is a public (L0) endpoint.
 func (c *ClobClient) GetCurrentRewardsMarkets(ctx context.Context) (json.RawMessage, error) {
-	resp, err := c.http.Get(ctx, EndpointRewardsMarketsCurrent, c.l0Headers(), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return json.RawMessage(raw), nil
+	return c.getJSON(ctx, EndpointRewardsMarketsCurrent, nil)
 }
 
 // GetCurrentRewards is an alias for GetCurrentRewardsMarkets.
@@ -114,19 +65,7 @@ func (c *ClobClient) GetCurrentRewards(ctx context.Context) (json.RawMessage, er
 // by its condition ID. The response is returned as raw JSON since its structure
 // may vary. This is a public (L0) endpoint.
 func (c *ClobClient) GetRewardsForMarket(ctx context.Context, conditionID string) (json.RawMessage, error) {
-	path := EndpointRewardsMarket + conditionID
-
-	resp, err := c.http.Get(ctx, path, c.l0Headers(), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return json.RawMessage(raw), nil
+	return c.getJSON(ctx, EndpointRewardsMarket+conditionID, nil)
 }
 
 // GetRawRewardsForMarket is an alias for GetRewardsForMarket.
@@ -138,21 +77,10 @@ func (c *ClobClient) GetRawRewardsForMarket(ctx context.Context, conditionID str
 // markets. The response is returned as raw JSON since its structure may vary.
 // Requires L2 authentication.
 func (c *ClobClient) GetUserMarketRewards(ctx context.Context) (json.RawMessage, error) {
-	headers, err := c.l2Headers("GET", EndpointRewardsUserMarkets, "")
+	raw, err := c.send(ctx, apiRequest{method: http.MethodGet, path: EndpointRewardsUserMarkets, auth: authL2})
 	if err != nil {
 		return nil, err
 	}
-
-	resp, err := c.http.Get(ctx, EndpointRewardsUserMarkets, headers, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
 	return json.RawMessage(raw), nil
 }
 
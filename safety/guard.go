@@ -0,0 +1,370 @@
+// Package safety cancels resting orders when a market maker loses its view of
+// the exchange. A Guard watches the user-channel socket and the PostHeartbeat
+// chain; once either has been down longer than a grace period it cancels
+// orders, retrying until the exchange confirms, and writes every step to an
+// audit log.
+package safety
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+// Defaults applied by New.
+const (
+	DefaultGrace          = 10 * time.Second
+	DefaultRetryInterval  = time.Second
+	DefaultRequestTimeout = 5 * time.Second
+	DefaultCancelGrace    = 10 * time.Second
+)
+
+// Audit actions.
+const (
+	ActionStarted            = "started"
+	ActionSocketDown         = "socket_down"
+	ActionSocketUp           = "socket_up"
+	ActionHeartbeatFailed    = "heartbeat_failed"
+	ActionHeartbeatRecovered = "heartbeat_recovered"
+	ActionTripped            = "tripped"
+	ActionCancel             = "cancel"
+	ActionCancelFailed       = "cancel_failed"
+	ActionCancelAbandoned    = "cancel_abandoned"
+	ActionRearmed            = "rearmed"
+)
+
+// Config configures a Guard.
+type Config struct {
+	// Client issues heartbeats and cancels. Required.
+	Client *polymarket.ClobClient
+	// WS, if set, has its user-channel socket watched. A socket that is not
+	// connected counts as down, including one not yet opened when Run starts.
+	WS *ws.Client
+	// Grace is how long the socket or heartbeat chain may be down before
+	// orders are canceled. Default DefaultGrace.
+	Grace time.Duration
+	// Markets limits cancellation to these condition IDs via
+	// CancelMarketOrders. Empty cancels everything via CancelAll.
+	Markets []string
+
+	// HeartbeatInterval, if positive, makes the guard drive the heartbeat
+	// chain itself with PostHeartbeat(HeartbeatID). Otherwise report the
+	// outcome of your own heartbeats with ReportHeartbeat, or leave the chain
+	// unwatched.
+	HeartbeatInterval time.Duration
+	HeartbeatID       string
+
+	// RetryInterval spaces cancel retries. Default DefaultRetryInterval.
+	RetryInterval time.Duration
+	// RequestTimeout bounds each heartbeat and cancel request. Default
+	// DefaultRequestTimeout.
+	RequestTimeout time.Duration
+	// CancelGrace is how long a cancel sequence in progress keeps going
+	// after Run's context ends, so shutting down mid-outage does not leave
+	// stale quotes resting. Default DefaultCancelGrace.
+	CancelGrace time.Duration
+
+	// Audit receives one JSON object per line for every action. Nil
+	// disables the log.
+	Audit io.Writer
+	// OnAudit, if set, is called with every audit entry, for alerting.
+	OnAudit func(AuditEntry)
+}
+
+// AuditEntry is one audit log record.
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Reason  string    `json:"reason,omitempty"`
+	Market  string    `json:"market,omitempty"`
+	Attempt int       `json:"attempt,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Guard cancels orders after the user socket or the heartbeat chain has been
+// down for longer than the grace period. Each outage triggers one cancel
+// sequence; the guard re-arms once both are healthy again.
+type Guard struct {
+	cfg Config
+	now func() time.Time
+
+	mu         sync.Mutex
+	socketDown time.Time // zero while the socket is up or unwatched
+	lastBeat   time.Time // last successful heartbeat; zero while unwatched
+	beatErr    error
+	tripped    bool
+	cancels    sync.WaitGroup
+
+	auditMu sync.Mutex
+	enc     *json.Encoder
+}
+
+// New returns a guard for cfg. Call Run to start it.
+func New(cfg Config) (*Guard, error) {
+	if cfg.Client == nil {
+		return nil, errors.New("safety: Client is required")
+	}
+	if cfg.Grace <= 0 {
+		cfg.Grace = DefaultGrace
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = DefaultRequestTimeout
+	}
+	if cfg.CancelGrace <= 0 {
+		cfg.CancelGrace = DefaultCancelGrace
+	}
+	g := &Guard{cfg: cfg, now: time.Now}
+	if cfg.Audit != nil {
+		g.enc = json.NewEncoder(cfg.Audit)
+	}
+	return g, nil
+}
+
+// Run watches the socket and heartbeat chain until ctx ends, then waits for
+// any cancel sequence in progress, for at most Config.CancelGrace, and
+// returns ctx.Err().
+func (g *Guard) Run(ctx context.Context) error {
+	defer g.cancels.Wait()
+	g.audit(AuditEntry{Action: ActionStarted, Reason: fmt.Sprintf("grace %s", g.cfg.Grace)})
+
+	var states <-chan ws.ConnStatus
+	if g.cfg.WS != nil {
+		states = g.cfg.WS.StateChanges(ctx)
+		opened := false
+		for _, st := range g.cfg.WS.State() {
+			opened = opened || st.Channel == ws.ChannelUser
+			g.observe(st)
+		}
+		if !opened {
+			g.mu.Lock()
+			g.socketDown = g.now()
+			g.mu.Unlock()
+			g.audit(AuditEntry{Action: ActionSocketDown, Reason: "not opened"})
+		}
+	}
+
+	var beats <-chan time.Time
+	if g.cfg.HeartbeatInterval > 0 {
+		g.mu.Lock()
+		g.lastBeat = g.now()
+		g.mu.Unlock()
+		ticker := time.NewTicker(g.cfg.HeartbeatInterval)
+		defer ticker.Stop()
+		beats = ticker.C
+		g.beat(ctx)
+	}
+
+	check := time.NewTicker(max(min(g.cfg.Grace/4, time.Second), time.Millisecond))
+	defer check.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case st, ok := <-states:
+			if !ok {
+				states = nil
+				continue
+			}
+			g.observe(st)
+		case <-beats:
+			g.beat(ctx)
+		case <-check.C:
+			g.evaluate(ctx)
+		}
+	}
+}
+
+// ReportHeartbeat records the outcome of a heartbeat sent outside the guard.
+// The first report starts watching the chain.
+func (g *Guard) ReportHeartbeat(err error) {
+	now := g.now()
+	g.mu.Lock()
+	if g.lastBeat.IsZero() {
+		g.lastBeat = now
+	}
+	prev := g.beatErr
+	g.beatErr = err
+	if err == nil {
+		g.lastBeat = now
+	}
+	g.mu.Unlock()
+
+	switch {
+	case err != nil && prev == nil:
+		g.audit(AuditEntry{Action: ActionHeartbeatFailed, Error: err.Error()})
+	case err == nil && prev != nil:
+		g.audit(AuditEntry{Action: ActionHeartbeatRecovered})
+	}
+}
+
+func (g *Guard) beat(ctx context.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx, g.cfg.RequestTimeout)
+	defer cancel()
+	err := g.cfg.Client.PostHeartbeat(reqCtx, g.cfg.HeartbeatID)
+	if ctx.Err() != nil {
+		return
+	}
+	g.ReportHeartbeat(err)
+}
+
+// observe tracks the user socket. Market sockets are ignored.
+func (g *Guard) observe(st ws.ConnStatus) {
+	if st.Channel != ws.ChannelUser {
+		return
+	}
+	up := st.State == ws.StateConnected || st.State == ws.StateSubscribed
+	g.mu.Lock()
+	wasDown := !g.socketDown.IsZero()
+	switch {
+	case up:
+		g.socketDown = time.Time{}
+	case !wasDown:
+		g.socketDown = st.Since
+		if g.socketDown.IsZero() {
+			g.socketDown = g.now()
+		}
+	}
+	g.mu.Unlock()
+
+	switch {
+	case up && wasDown:
+		g.audit(AuditEntry{Action: ActionSocketUp})
+	case !up && !wasDown:
+		entry := AuditEntry{Action: ActionSocketDown, Reason: st.State.String()}
+		if st.LastError != nil {
+			entry.Error = st.LastError.Error()
+		}
+		g.audit(entry)
+	}
+}
+
+// evaluate trips the guard once an outage outlasts the grace period and
+// re-arms it after recovery.
+func (g *Guard) evaluate(ctx context.Context) {
+	now := g.now()
+	g.mu.Lock()
+	reason := ""
+	if !g.socketDown.IsZero() && now.Sub(g.socketDown) >= g.cfg.Grace {
+		reason = fmt.Sprintf("user socket down for %s", now.Sub(g.socketDown).Round(time.Millisecond))
+	} else if !g.lastBeat.IsZero() && now.Sub(g.lastBeat) >= g.cfg.Grace {
+		reason = fmt.Sprintf("no successful heartbeat for %s", now.Sub(g.lastBeat).Round(time.Millisecond))
+	}
+	healthy := g.socketDown.IsZero() && (g.lastBeat.IsZero() || g.beatErr == nil)
+	trip := reason != "" && !g.tripped
+	rearm := reason == "" && g.tripped && healthy
+	if trip {
+		g.tripped = true
+		g.cancels.Add(1)
+	}
+	if rearm {
+		g.tripped = false
+	}
+	g.mu.Unlock()
+
+	if trip {
+		g.audit(AuditEntry{Action: ActionTripped, Reason: reason})
+		go func() {
+			defer g.cancels.Done()
+			g.cancel(ctx, reason)
+		}()
+	}
+	if rearm {
+		g.audit(AuditEntry{Action: ActionRearmed})
+	}
+}
+
+// cancel cancels orders until every request succeeds, or until CancelGrace
+// after ctx ends. The sequence runs to completion even if the outage ends
+// meanwhile, since quotes placed before it may be stale.
+func (g *Guard) cancel(ctx context.Context, reason string) {
+	ctx, stop := g.cancelContext(ctx)
+	defer stop()
+
+	pending := append([]string(nil), g.cfg.Markets...)
+	for attempt := 1; ; attempt++ {
+		if len(g.cfg.Markets) == 0 {
+			if g.cancelOnce(ctx, reason, "", attempt) {
+				return
+			}
+		} else {
+			failed := pending[:0]
+			for _, market := range pending {
+				if !g.cancelOnce(ctx, reason, market, attempt) {
+					failed = append(failed, market)
+				}
+			}
+			if pending = failed; len(pending) == 0 {
+				return
+			}
+		}
+
+		timer := time.NewTimer(g.cfg.RetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			g.audit(AuditEntry{Action: ActionCancelAbandoned, Reason: reason, Attempt: attempt, Error: context.Cause(ctx).Error()})
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// cancelContext detaches a cancel sequence from the guard's ctx and ends it
+// CancelGrace after ctx does.
+func (g *Guard) cancelContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached, abandon := context.WithCancelCause(context.WithoutCancel(ctx))
+	stopAfter := context.AfterFunc(ctx, func() {
+		timer := time.AfterFunc(g.cfg.CancelGrace, func() {
+			abandon(fmt.Errorf("safety: still canceling %s after shutdown: %w", g.cfg.CancelGrace, ctx.Err()))
+		})
+		context.AfterFunc(detached, func() { timer.Stop() })
+	})
+	return detached, func() {
+		stopAfter()
+		abandon(nil)
+	}
+}
+
+// cancelOnce issues one cancel request; an empty market means CancelAll.
+func (g *Guard) cancelOnce(ctx context.Context, reason, market string, attempt int) bool {
+	reqCtx, cancel := context.WithTimeout(ctx, g.cfg.RequestTimeout)
+	defer cancel()
+	var err error
+	if market == "" {
+		err = g.cfg.Client.CancelAll(reqCtx)
+	} else {
+		err = g.cfg.Client.CancelMarketOrders(reqCtx, market, "")
+	}
+	entry := AuditEntry{Action: ActionCancel, Reason: reason, Market: market, Attempt: attempt}
+	if err != nil {
+		entry.Action = ActionCancelFailed
+		entry.Error = err.Error()
+	}
+	g.audit(entry)
+	return err == nil
+}
+
+func (g *Guard) audit(e AuditEntry) {
+	if e.Time.IsZero() {
+		e.Time = g.now()
+	}
+	g.auditMu.Lock()
+	if g.enc != nil {
+		_ = g.enc.Encode(e)
+	}
+	g.auditMu.Unlock()
+	if g.cfg.OnAudit != nil {
+		g.cfg.OnAudit(e)
+	}
+}
@@ -0,0 +1,231 @@
+package safety
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+func TestGuardCancelsAfterHeartbeatOutageAndRearms(t *testing.T) {
+	var beatOK atomic.Bool
+	var cancelCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case polymarket.EndpointHeartbeat:
+			if !beatOK.Load() {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"heartbeat expired"}`))
+				return
+			}
+		case polymarket.EndpointCancelMarketOrders:
+			// The first cancel fails and must be retried.
+			if cancelCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"try again"}`))
+				return
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := polymarket.NewClobClient(
+		polymarket.WithBaseURL(srv.URL),
+		polymarket.WithAddress("0x0000000000000000000000000000000000000001"),
+		polymarket.WithCreds(polymarket.ApiCreds{
+			ApiKey:        "k",
+			ApiSecret:     base64.URLEncoding.EncodeToString([]byte("secret")),
+			ApiPassphrase: "p",
+		}),
+	)
+
+	var mu sync.Mutex
+	var actions []string
+	var log bytes.Buffer
+	guard, err := New(Config{
+		Client:            client,
+		Grace:             40 * time.Millisecond,
+		Markets:           []string{"0xmarket"},
+		HeartbeatInterval: 5 * time.Millisecond,
+		RetryInterval:     5 * time.Millisecond,
+		Audit:             &log,
+		OnAudit: func(e AuditEntry) {
+			mu.Lock()
+			actions = append(actions, e.Action)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- guard.Run(ctx) }()
+
+	saw := func(action string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, a := range actions {
+			if a == action {
+				return true
+			}
+		}
+		return false
+	}
+	waitFor(t, func() bool { return saw(ActionCancel) })
+	if saw(ActionRearmed) {
+		t.Fatalf("rearmed while heartbeats still fail")
+	}
+
+	beatOK.Store(true)
+	waitFor(t, func() bool { return saw(ActionRearmed) })
+	cancel()
+	<-done
+
+	mu.Lock()
+	got := strings.Join(actions, ",")
+	mu.Unlock()
+	want := "started,heartbeat_failed,tripped,cancel_failed,cancel,"
+	if !strings.HasPrefix(got, want) || strings.Count(got, ActionTripped) != 1 {
+		t.Fatalf("unexpected audit sequence %s", got)
+	}
+	if cancelCalls.Load() != 2 {
+		t.Fatalf("expected 2 cancel attempts, got %d", cancelCalls.Load())
+	}
+	if !strings.Contains(log.String(), `"market":"0xmarket","attempt":2`) {
+		t.Fatalf("audit log missing cancel entry:\n%s", log.String())
+	}
+}
+
+func TestGuardFinishesCancelAfterShutdown(t *testing.T) {
+	var cancelCalls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case polymarket.EndpointHeartbeat:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"heartbeat expired"}`))
+			return
+		case polymarket.EndpointCancelAll:
+			// The first cancel is in flight when the guard shuts down, and
+			// fails; the sequence must still retry to success.
+			if cancelCalls.Add(1) == 1 {
+				close(started)
+				<-release
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"try again"}`))
+				return
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := polymarket.NewClobClient(
+		polymarket.WithBaseURL(srv.URL),
+		polymarket.WithAddress("0x0000000000000000000000000000000000000001"),
+		polymarket.WithCreds(polymarket.ApiCreds{
+			ApiKey:        "k",
+			ApiSecret:     base64.URLEncoding.EncodeToString([]byte("secret")),
+			ApiPassphrase: "p",
+		}),
+	)
+	var mu sync.Mutex
+	var actions []string
+	guard, err := New(Config{
+		Client:            client,
+		Grace:             20 * time.Millisecond,
+		HeartbeatInterval: 5 * time.Millisecond,
+		RetryInterval:     5 * time.Millisecond,
+		OnAudit: func(e AuditEntry) {
+			mu.Lock()
+			actions = append(actions, e.Action)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- guard.Run(ctx) }()
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("guard never canceled")
+	}
+	cancel()
+	close(release)
+	<-done
+
+	mu.Lock()
+	got := strings.Join(actions, ",")
+	mu.Unlock()
+	if !strings.HasSuffix(got, "tripped,cancel_failed,cancel") || cancelCalls.Load() != 2 {
+		t.Fatalf("cancel sequence did not finish after shutdown: %s (%d calls)", got, cancelCalls.Load())
+	}
+}
+
+func TestGuardTripsWhenUserSocketNeverOpens(t *testing.T) {
+	var cancelCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != polymarket.EndpointCancelAll {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		cancelCalls.Add(1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := polymarket.NewClobClient(
+		polymarket.WithBaseURL(srv.URL),
+		polymarket.WithAddress("0x0000000000000000000000000000000000000001"),
+		polymarket.WithCreds(polymarket.ApiCreds{
+			ApiKey:        "k",
+			ApiSecret:     base64.URLEncoding.EncodeToString([]byte("secret")),
+			ApiPassphrase: "p",
+		}),
+	)
+	stream := ws.NewClient()
+	defer stream.Close()
+	// A grace this short would make a zero check interval.
+	guard, err := New(Config{Client: client, WS: stream, Grace: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- guard.Run(ctx) }()
+	waitFor(t, func() bool { return cancelCalls.Load() > 0 })
+	cancel()
+	<-done
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("condition not met before timeout")
+}
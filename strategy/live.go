@@ -0,0 +1,211 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+// seenTrades is how many recent trade IDs RunLive remembers to report each
+// trade once. A trade's status updates arrive within minutes, long before its
+// ID is evicted.
+const seenTrades = 10_000
+
+// LiveConfig configures RunLive.
+type LiveConfig struct {
+	Client   *polymarket.ClobClient
+	WS       *ws.Client
+	AssetIDs []string
+	// Creds and Markets (condition IDs) enable fill delivery via the
+	// authenticated user channel. Without them OnFill is never called.
+	Creds   *polymarket.ApiCreds
+	Markets []string
+	// RequestTimeout bounds each order and cancel request. Default 10s.
+	RequestTimeout time.Duration
+}
+
+// RunLive drives s with live market data for cfg.AssetIDs until ctx is
+// canceled or the market streams close. Orders placed from callbacks are
+// posted synchronously, so callbacks still run one at a time.
+func RunLive(ctx context.Context, cfg LiveConfig, s Strategy) error {
+	if cfg.Client == nil || cfg.WS == nil {
+		return errors.New("strategy: Client and WS are required")
+	}
+	if len(cfg.AssetIDs) == 0 {
+		return errors.New("strategy: at least one asset ID is required")
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ex := &liveExchange{
+		ctx:     ctx,
+		client:  cfg.Client,
+		timeout: cfg.RequestTimeout,
+		orders:  make(map[string]Order),
+		seen:    make(map[string]struct{}),
+	}
+
+	books := cfg.WS.SubscribeOrderBook(ctx, cfg.AssetIDs...)
+	prices := cfg.WS.SubscribePrices(ctx, cfg.AssetIDs...)
+	trades := cfg.WS.SubscribeLastTradePrice(ctx, cfg.AssetIDs...)
+	var fills <-chan ws.TradeUpdate
+	if cfg.Creds != nil && len(cfg.Markets) > 0 {
+		fills = cfg.WS.SubscribeTrades(ctx, cfg.Creds.ApiKey, cfg.Creds.ApiSecret, cfg.Creds.ApiPassphrase, cfg.Markets...)
+	}
+
+	for books != nil || prices != nil || trades != nil {
+		select {
+		case <-ctx.Done():
+			return nil
+		case b, ok := <-books:
+			if !ok {
+				books = nil
+				continue
+			}
+			s.OnBook(ex, b)
+		case p, ok := <-prices:
+			if !ok {
+				prices = nil
+				continue
+			}
+			s.OnPriceChange(ex, p)
+		case t, ok := <-trades:
+			if !ok {
+				trades = nil
+				continue
+			}
+			s.OnLastTrade(ex, t)
+		case u, ok := <-fills:
+			if !ok {
+				fills = nil
+				continue
+			}
+			for _, f := range ex.fillsFrom(u) {
+				s.OnFill(ex, f)
+			}
+		}
+	}
+	return nil
+}
+
+// liveExchange implements Exchange against the CLOB REST API.
+type liveExchange struct {
+	ctx     context.Context
+	client  *polymarket.ClobClient
+	timeout time.Duration
+
+	mu       sync.Mutex
+	orders   map[string]Order    // placed orders by ID, for fill attribution
+	seen     map[string]struct{} // trade IDs already reported
+	seenRing []string            // seen IDs in arrival order, for eviction
+	seenNext int
+}
+
+func (e *liveExchange) Now() time.Time { return time.Now() }
+
+func (e *liveExchange) PlaceOrder(o Order) (string, error) {
+	orderType := o.Type
+	if orderType == "" {
+		orderType = polymarket.GTC
+	}
+	ctx, cancel := context.WithTimeout(e.ctx, e.timeout)
+	defer cancel()
+
+	resp, err := e.client.CreateAndPostOrder(ctx, polymarket.OrderArgs{
+		TokenID: o.TokenID,
+		Price:   o.Price,
+		Size:    o.Size,
+		Side:    o.Side,
+	}, orderType, o.PostOnly)
+	if err != nil {
+		return "", err
+	}
+	if resp.ErrorMsg != "" && resp.OrderID == "" {
+		return "", fmt.Errorf("strategy: order rejected: %s", resp.ErrorMsg)
+	}
+
+	e.mu.Lock()
+	e.orders[resp.OrderID] = o
+	e.mu.Unlock()
+	return resp.OrderID, nil
+}
+
+func (e *liveExchange) CancelOrder(orderID string) error {
+	ctx, cancel := context.WithTimeout(e.ctx, e.timeout)
+	defer cancel()
+	return e.client.CancelOrder(ctx, orderID)
+}
+
+// fillsFrom extracts fills of our own orders from a user-channel trade. Each
+// trade is reported once, on its first status update.
+func (e *liveExchange) fillsFrom(u ws.TradeUpdate) []Fill {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.markSeen(u.ID) {
+		return nil
+	}
+
+	now := time.Now()
+	var fills []Fill
+	if o, ok := e.orders[u.TakerOrderID]; ok {
+		fills = append(fills, Fill{
+			OrderID: u.TakerOrderID,
+			TokenID: o.TokenID,
+			Side:    o.Side,
+			Price:   parseDecimal(u.Price),
+			Size:    parseDecimal(u.Size),
+			Time:    now,
+		})
+	}
+	for _, m := range u.MakerOrders {
+		o, ok := e.orders[m.OrderID]
+		if !ok {
+			continue
+		}
+		fills = append(fills, Fill{
+			OrderID: m.OrderID,
+			TokenID: o.TokenID,
+			Side:    o.Side,
+			Price:   parseDecimal(m.Price),
+			Size:    parseDecimal(m.MatchedAmount),
+			Maker:   true,
+			Time:    now,
+		})
+	}
+	return fills
+}
+
+// markSeen records a trade ID, evicting the oldest once seenTrades are held,
+// and reports whether it was new.
+func (e *liveExchange) markSeen(id string) bool {
+	if _, ok := e.seen[id]; ok {
+		return false
+	}
+	if len(e.seenRing) < seenTrades {
+		e.seenRing = append(e.seenRing, id)
+	} else {
+		delete(e.seen, e.seenRing[e.seenNext])
+		e.seenRing[e.seenNext] = id
+		e.seenNext = (e.seenNext + 1) % seenTrades
+	}
+	e.seen[id] = struct{}{}
+	return true
+}
+
+func parseDecimal(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
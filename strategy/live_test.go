@@ -0,0 +1,122 @@
+package strategy
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/replay"
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+// bidder bids once on the first book and stops the run.
+type bidder struct {
+	Base
+	stop   context.CancelFunc
+	ex     *liveExchange
+	placed string
+	err    error
+}
+
+func (b *bidder) OnBook(ex Exchange, book ws.BookUpdate) {
+	if b.placed != "" || b.err != nil {
+		return
+	}
+	b.ex = ex.(*liveExchange)
+	b.placed, b.err = ex.PlaceOrder(Order{
+		TokenID: book.AssetID,
+		Side:    polymarket.Buy,
+		Price:   decimal.RequireFromString("0.4"),
+		Size:    decimal.NewFromInt(10),
+	})
+	b.stop()
+}
+
+func TestRunLivePlacesOrdersAndReportsFillsOnce(t *testing.T) {
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case polymarket.EndpointTickSize:
+			_, _ = w.Write([]byte(`{"minimum_tick_size":0.01}`))
+		case polymarket.EndpointNegRisk:
+			_, _ = w.Write([]byte(`{"neg_risk":false}`))
+		case polymarket.EndpointFeeRate:
+			_, _ = w.Write([]byte(`{"base_fee":0}`))
+		case polymarket.EndpointPostOrder:
+			posts.Add(1)
+			_, _ = w.Write([]byte(`{"success":true,"orderID":"0xmine","status":"live"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := polymarket.NewClobClient(
+		polymarket.WithBaseURL(srv.URL),
+		polymarket.WithSigner(key),
+		polymarket.WithCreds(polymarket.ApiCreds{
+			ApiKey:        "k",
+			ApiSecret:     base64.URLEncoding.EncodeToString([]byte("secret")),
+			ApiPassphrase: "p",
+		}),
+	)
+
+	var rec strings.Builder
+	recorder := replay.NewRecorderWriter(&rec)
+	recorder.RecordWSFrame(ws.DefaultEndpoint+"/ws/market", replay.DirectionIn,
+		[]byte(`{"event_type":"book","asset_id":"1","market":"m","bids":[{"price":"0.39","size":"5"}],"asks":[{"price":"0.41","size":"5"}]}`))
+	rp, err := replay.LoadReader(strings.NewReader(rec.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wsClient := ws.NewClient(ws.WithEndpoint("ws://127.0.0.1:1"), ws.WithReplay(rp))
+	defer wsClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	s := &bidder{stop: cancel}
+	if err := RunLive(ctx, LiveConfig{Client: client, WS: wsClient, AssetIDs: []string{"1"}}, s); err != nil {
+		t.Fatalf("run live: %v", err)
+	}
+	if s.err != nil || s.placed != "0xmine" || posts.Load() != 1 {
+		t.Fatalf("order not placed: id=%q err=%v posts=%d", s.placed, s.err, posts.Load())
+	}
+
+	// Status updates of a trade repeat its ID; the fill is reported once.
+	ex := s.ex
+	update := ws.TradeUpdate{ID: "t1", Price: "0.4", Size: "3", TakerOrderID: "0xother",
+		MakerOrders: []ws.MakerFill{{OrderID: "0xmine", MatchedAmount: "3", Price: "0.4"}}}
+	fills := ex.fillsFrom(update)
+	if len(fills) != 1 || !fills[0].Maker || fills[0].TokenID != "1" || !fills[0].Size.Equal(decimal.NewFromInt(3)) {
+		t.Fatalf("unexpected fills %+v", fills)
+	}
+	update.Status = "CONFIRMED"
+	if again := ex.fillsFrom(update); len(again) != 0 {
+		t.Fatalf("trade reported twice: %+v", again)
+	}
+
+	// Remembered trade IDs are bounded.
+	for i := range seenTrades {
+		ex.fillsFrom(ws.TradeUpdate{ID: "x" + strconv.Itoa(i)})
+	}
+	if len(ex.seen) != seenTrades || len(ex.seenRing) != seenTrades {
+		t.Fatalf("seen grew to %d", len(ex.seen))
+	}
+	if _, ok := ex.seen["t1"]; ok {
+		t.Fatalf("oldest trade ID was not evicted")
+	}
+}
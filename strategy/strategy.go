@@ -0,0 +1,66 @@
+// Package strategy defines the interface trading strategies implement and a
+// live runner that drives one against the exchange. The backtest package
+// drives the same interface from recorded market data, so a strategy can be
+// evaluated offline and deployed unchanged.
+package strategy
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/ws"
+)
+
+// Order is a limit order request from a strategy.
+type Order struct {
+	TokenID string
+	Side    polymarket.Side
+	Price   decimal.Decimal
+	Size    decimal.Decimal
+	// Type defaults to GTC. FOK and FAK orders never rest.
+	Type     polymarket.OrderType
+	PostOnly bool
+}
+
+// Fill reports an execution of one of the strategy's orders.
+type Fill struct {
+	OrderID string
+	TokenID string
+	Side    polymarket.Side
+	Price   decimal.Decimal
+	Size    decimal.Decimal
+	// Maker is true when the order was resting on the book.
+	Maker bool
+	Time  time.Time
+}
+
+// Exchange is what a strategy acts on. Implementations deliver callbacks
+// serially, so strategies need no locking of their own.
+type Exchange interface {
+	// Now returns the current time: wall-clock live, simulated in a backtest.
+	Now() time.Time
+	// PlaceOrder submits an order and returns its ID.
+	PlaceOrder(o Order) (string, error)
+	// CancelOrder cancels an open order by ID.
+	CancelOrder(orderID string) error
+}
+
+// Strategy receives market data and fills. Callbacks are never invoked
+// concurrently.
+type Strategy interface {
+	OnBook(ex Exchange, book ws.BookUpdate)
+	OnPriceChange(ex Exchange, change ws.PriceChange)
+	OnLastTrade(ex Exchange, trade ws.LastTradePrice)
+	OnFill(ex Exchange, fill Fill)
+}
+
+// Base is a Strategy with no-op callbacks. Embed it to implement only the
+// callbacks you need.
+type Base struct{}
+
+func (Base) OnBook(Exchange, ws.BookUpdate)          {}
+func (Base) OnPriceChange(Exchange, ws.PriceChange)  {}
+func (Base) OnLastTrade(Exchange, ws.LastTradePrice) {}
+func (Base) OnFill(Exchange, Fill)                   {}
@@ -2,11 +2,8 @@ package client
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"iter"
-
-	"github.com/lubluniky/clob-client-go/internal/transport"
+	"net/http"
 )
 
 // GetTrades returns an iterator over trades with auto-pagination. Requires L2
@@ -14,10 +11,6 @@ import (
 // range.
 func (c *ClobClient) GetTrades(ctx context.Context, params TradeParams) iter.Seq2[Trade, error] {
 	return paginate[Trade](ctx, func(cursor string) (PaginatedResponse[Trade], error) {
-		headers, err := c.l2Headers("GET", EndpointTrades, "")
-		if err != nil {
-			return PaginatedResponse[Trade]{}, err
-		}
 		query := make(map[string]string)
 		if params.Market != "" {
 			query["market"] = params.Market
@@ -40,28 +33,17 @@ func (c *ClobClient) GetTrades(ctx context.Context, params TradeParams) iter.Seq
 		if cursor != "" {
 			query["next_cursor"] = cursor
 		}
-		resp, err := c.http.Get(ctx, EndpointTrades, headers, query)
-		if err != nil {
-			return PaginatedResponse[Trade]{}, err
-		}
-		body, err := transport.ParseResponse(resp)
-		if err != nil {
-			return PaginatedResponse[Trade]{}, err
-		}
-		var page PaginatedResponse[Trade]
-		if err := json.Unmarshal(body, &page); err != nil {
-			return PaginatedResponse[Trade]{}, fmt.Errorf("polymarket: parsing trades: %w", err)
-		}
-		return page, nil
+		return call[PaginatedResponse[Trade]](ctx, c, apiRequest{
+			method: http.MethodGet,
+			path:   EndpointTrades,
+			query:  query,
+			auth:   authL2,
+		}, "trades")
 	})
 }
 
 // GetTradesPaginated returns one page of trades and server paging metadata.
 func (c *ClobClient) GetTradesPaginated(ctx context.Context, params TradeParams, cursor string) (PaginatedResponse[Trade], error) {
-	headers, err := c.l2Headers("GET", EndpointTrades, "")
-	if err != nil {
-		return PaginatedResponse[Trade]{}, err
-	}
 	query := make(map[string]string)
 	if params.Market != "" {
 		query["market"] = params.Market
@@ -84,40 +66,16 @@ func (c *ClobClient) GetTradesPaginated(ctx context.Context, params TradeParams,
 	if cursor != "" {
 		query["next_cursor"] = cursor
 	}
-
-	resp, err := c.http.Get(ctx, EndpointTrades, headers, query)
-	if err != nil {
-		return PaginatedResponse[Trade]{}, err
-	}
-	body, err := transport.ParseResponse(resp)
-	if err != nil {
-		return PaginatedResponse[Trade]{}, err
-	}
-	var page PaginatedResponse[Trade]
-	if err := json.Unmarshal(body, &page); err != nil {
-		return PaginatedResponse[Trade]{}, fmt.Errorf("polymarket: parsing trades page: %w", err)
-	}
-	return page, nil
+	return call[PaginatedResponse[Trade]](ctx, c, apiRequest{
+		method: http.MethodGet,
+		path:   EndpointTrades,
+		query:  query,
+		auth:   authL2,
+	}, "trades page")
 }
 
 // GetMarketTradesEvents returns trade events for a market identified by its
 // condition ID. This is a public (L0) endpoint.
 func (c *ClobClient) GetMarketTradesEvents(ctx context.Context, conditionID string) ([]TradeEvent, error) {
-	path := EndpointMarketTradesEvents + conditionID
-
-	resp, err := c.http.Get(ctx, path, c.l0Headers(), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	raw, err := transport.ParseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	var events []TradeEvent
-	if err := json.Unmarshal(raw, &events); err != nil {
-		return nil, fmt.Errorf("polymarket: parsing trade events: %w", err)
-	}
-	return events, nil
+	return call[[]TradeEvent](ctx, c, apiRequest{method: http.MethodGet, path: EndpointMarketTradesEvents + conditionID}, "trade events")
 }
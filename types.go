@@ -2,6 +2,7 @@ package client
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -157,8 +158,12 @@ type OrderArgs struct {
 	Side       Side
 	FeeRateBps int
 	Nonce      int
+	// Expiration is the unix time a GTD order expires at; zero means none.
 	Expiration int
-	Taker      string
+	// TTL, when Expiration is zero, sets it to TTL from the client's Now,
+	// which is server-corrected when clock sync is enabled.
+	TTL   time.Duration
+	Taker string
 	// Optional override; defaults to the client's configured signature type.
 	SignatureType SignatureType
 }
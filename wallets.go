@@ -0,0 +1,129 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Init code hashes of the Polymarket proxy wallet and Gnosis Safe proxies,
+// used for CREATE2 address derivation.
+var (
+	ProxyWalletInitCodeHash = common.HexToHash("0xd21df8dc65880a8606f09fe0ce3df9b8869287ab0b058be05aa9e8af6330a00b")
+	SafeInitCodeHash        = common.HexToHash("0x2bce2127ff07fb632d16c8347c4ebf501f4841168bed00d9e6ef715ddb6fcecf")
+)
+
+// walletFactories are the contracts that deploy user wallets on a chain. A
+// zero factory means that wallet type is not deployed there.
+type walletFactories struct {
+	proxy common.Address
+	safe  common.Address
+}
+
+var factoriesByChain = map[int]walletFactories{
+	PolygonChainID: {
+		proxy: common.HexToAddress("0xaB45c5A4B0c941a2F231C04C3f49182e1A254052"),
+		safe:  common.HexToAddress("0xaacFeEa03eb1561C4e67d661e40682Bd20E3541b"),
+	},
+	AmoyChainID: {
+		safe: common.HexToAddress("0xaacFeEa03eb1561C4e67d661e40682Bd20E3541b"),
+	},
+}
+
+// DeriveProxyWallet returns the address of the Polymarket proxy wallet
+// (signature type PolyProxy) owned by eoa on chainID. The wallet need not be
+// deployed yet; its address is fixed by CREATE2.
+func DeriveProxyWallet(eoa common.Address, chainID int) (common.Address, error) {
+	factory := factoriesByChain[chainID].proxy
+	if factory == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("polymarket: no proxy wallet factory on chain %d", chainID)
+	}
+	salt := crypto.Keccak256Hash(eoa.Bytes())
+	return crypto.CreateAddress2(factory, salt, ProxyWalletInitCodeHash.Bytes()), nil
+}
+
+// DeriveSafeWallet returns the address of the Gnosis Safe (signature type
+// PolyGnosisSafe) that Polymarket deploys for eoa on chainID.
+func DeriveSafeWallet(eoa common.Address, chainID int) (common.Address, error) {
+	factory := factoriesByChain[chainID].safe
+	if factory == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("polymarket: no safe factory on chain %d", chainID)
+	}
+	// The salt is keccak256(abi.encode(owner)): the address left-padded to a
+	// full word, unlike the packed encoding of the proxy factory.
+	salt := crypto.Keccak256Hash(common.LeftPadBytes(eoa.Bytes(), 32))
+	return crypto.CreateAddress2(factory, salt, SafeInitCodeHash.Bytes()), nil
+}
+
+// FunderAddress returns the address that holds funds and makes orders for
+// signer under sigType: signer itself for EOA, otherwise its derived proxy
+// wallet or Safe.
+func FunderAddress(signer common.Address, sigType SignatureType, chainID int) (common.Address, error) {
+	switch sigType {
+	case EOA:
+		return signer, nil
+	case PolyProxy:
+		return DeriveProxyWallet(signer, chainID)
+	case PolyGnosisSafe:
+		return DeriveSafeWallet(signer, chainID)
+	}
+	return common.Address{}, fmt.Errorf("polymarket: unknown signature type %d", sigType)
+}
+
+// WithFunderValidation controls whether order creation checks that the
+// funder matches the signature type (see ValidateFunder). Default true;
+// disable it for wallets deployed outside Polymarket's factories.
+//
+// The check is on by default, which changed existing behavior: an EOA client
+// with a separate funder, or a PolyProxy or PolyGnosisSafe client whose
+// funder is not the wallet derived from its signer, used to sign orders the
+// exchange would reject and now fails in CreateOrder with a ValidationError.
+// Pass false to restore the old behavior.
+func WithFunderValidation(enabled bool) ClientOption {
+	return func(c *ClobClient) {
+		c.skipFunderCheck = !enabled
+	}
+}
+
+// ValidateFunder checks the client's funder against its default signature
+// type: an EOA must make its own orders, and a PolyProxy or PolyGnosisSafe
+// funder must be the wallet derived from the signer. CreateOrder and
+// CreateMarketOrder run the same check for the signature type of each order.
+func (c *ClobClient) ValidateFunder() error {
+	return c.validateFunder(c.signatureType)
+}
+
+func (c *ClobClient) validateFunder(sigType SignatureType) error {
+	if c.skipFunderCheck || c.signer == nil {
+		return nil
+	}
+	signer := crypto.PubkeyToAddress(c.signer.PublicKey)
+	maker := signer
+	if c.funder != nil {
+		maker = *c.funder
+	}
+	want, err := FunderAddress(signer, sigType, c.chainID)
+	if err != nil {
+		return &ValidationError{Field: "funder", Message: err.Error()}
+	}
+	if maker != want {
+		return &ValidationError{
+			Field:   "funder",
+			Message: fmt.Sprintf("funder %s does not match signature type %d for signer %s; expected %s", maker.Hex(), sigType, signer.Hex(), want.Hex()),
+		}
+	}
+	return nil
+}
+
+// deriveFunder sets the funder of a proxy or Safe client configured without
+// one, so orders are made by the wallet that holds the funds.
+func (c *ClobClient) deriveFunder() {
+	if c.funder != nil || c.signer == nil || (c.signatureType != PolyProxy && c.signatureType != PolyGnosisSafe) {
+		return
+	}
+	funder, err := FunderAddress(crypto.PubkeyToAddress(c.signer.PublicKey), c.signatureType, c.chainID)
+	if err == nil {
+		c.funder = &funder
+	}
+}
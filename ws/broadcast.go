@@ -0,0 +1,53 @@
+package ws
+
+import "sync"
+
+// broadcaster fans values out to subscribers without blocking; slow
+// subscribers miss values.
+type broadcaster[T any] struct {
+	mu   sync.Mutex
+	subs map[uint64]chan T
+	next uint64
+}
+
+// subscribe registers a subscriber and returns a function that unregisters
+// and closes it.
+func (b *broadcaster[T]) subscribe() (<-chan T, func()) {
+	ch := make(chan T, channelBufferSize)
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[uint64]chan T)
+	}
+	b.next++
+	id := b.next
+	b.subs[id] = ch
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if sub, ok := b.subs[id]; ok {
+			delete(b.subs, id)
+			close(sub)
+		}
+	}
+}
+
+func (b *broadcaster[T]) publish(v T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
+
+func (b *broadcaster[T]) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, ch := range b.subs {
+		close(ch)
+		delete(b.subs, id)
+	}
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math"
-	"math/rand/v2"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"github.com/lubluniky/clob-client-go/observe"
+	"github.com/lubluniky/clob-client-go/replay"
 )
 
 const (
@@ -27,10 +28,34 @@ const (
 type Client struct {
 	endpoint  string
 	parentCtx context.Context
-
-	mu         sync.Mutex
-	marketConn *connection
-	userConn   *connection
+	hooks     observe.Hooks
+	recorder  *replay.Recorder
+	replayer  *replay.Replayer
+
+	// Market channel sharding limits; see shard.go.
+	minShards   int
+	maxPerShard int
+
+	// cfg tunes every socket; see options.go.
+	cfg connConfig
+
+	// User channel credentials; see creds.go.
+	credsMu sync.RWMutex
+	creds   CredsSource
+
+	// subscribeTimeout bounds the Subscribe*Confirmed variants.
+	subscribeTimeout time.Duration
+
+	// Error frame and state change subscribers; see errors.go and state.go.
+	errs   broadcaster[ErrorEvent]
+	states broadcaster[ConnStatus]
+
+	mu       sync.Mutex
+	userConn *connection
+	// Market channel shards, guarded by mu.
+	marketShards []*connection
+	shardOf      map[string]int // asset ID -> index into marketShards
+	shardLoad    []int          // assets assigned per shard
 }
 
 // Option configures the WebSocket client.
@@ -51,11 +76,19 @@ func WithConnectionContext(ctx context.Context) Option {
 	}
 }
 
+// WithHooks installs instrumentation hooks that observe connects, disconnects
+// and inbound messages on every channel.
+func WithHooks(h observe.Hooks) Option {
+	return func(c *Client) { c.hooks = h }
+}
+
 // NewClient creates a new WebSocket client.
 func NewClient(opts ...Option) *Client {
 	c := &Client{
-		endpoint:  DefaultEndpoint,
-		parentCtx: context.Background(),
+		endpoint:         DefaultEndpoint,
+		parentCtx:        context.Background(),
+		subscribeTimeout: DefaultSubscribeTimeout,
+		cfg:              defaultConnConfig(),
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -68,16 +101,35 @@ type connection struct {
 	url    string
 	ctx    context.Context
 	cancel context.CancelFunc
-	conn   *websocket.Conn
+	conn   wsConn
 	connMu sync.Mutex
 
+	// hooks observes connection lifecycle and traffic; nil disables.
+	hooks observe.Hooks
+	// cfg holds reconnect, heartbeat and read tuning.
+	cfg connConfig
+	// recorder captures every non-heartbeat frame; nil disables.
+	recorder *replay.Recorder
+	// dial opens the underlying socket; replaced in replay mode.
+	dial func(ctx context.Context, url string) (wsConn, error)
+
 	// writeMu serialises all WebSocket write operations.
 	// gorilla/websocket does not support concurrent writers.
 	writeMu sync.Mutex
 
-	// Subscription tracking for reconnection
-	subsMu sync.Mutex
-	subs   []SubscriptionRequest // tracked for re-subscribe on reconnect
+	// reg refcounts subscriptions and drives re-subscribe on reconnect.
+	reg *registry
+	// onIdle, if set, is called with IDs no consumer holds any more.
+	onIdle func(ids []string)
+	// onError receives server error frames; see errors.go.
+	onError func(ev ErrorEvent)
+
+	// shard is the market shard index (0 on the user channel).
+	shard int
+	// health tracks state, traffic and heartbeat timing; see state.go.
+	health health
+	// onState, if set, receives every state change.
+	onState func(st ConnStatus)
 
 	// Message broadcast
 	listeners []listener
@@ -86,6 +138,8 @@ type connection struct {
 
 	// closed is set by close() to signal that dispatch should stop.
 	closed bool
+	// abandoned is set when the backoff policy gave up reconnecting.
+	abandoned atomic.Bool
 
 	// Heartbeat tracking
 	lastPong time.Time
@@ -96,38 +150,110 @@ type listener struct {
 	id        uint64
 	eventType string // filter by event_type, empty = all
 	ch        chan json.RawMessage
+
+	// Typed listeners (Client.Subscribe) receive decoded events on events
+	// instead, filtered by types (nil = all).
+	events chan Event
+	types  map[string]struct{}
 }
 
-// newConnection creates and starts a connection to the given WS URL.
-func newConnection(parentCtx context.Context, url string) *connection {
-	ctx, cancel := context.WithCancel(parentCtx)
-	c := &connection{
-		url:    url,
-		ctx:    ctx,
-		cancel: cancel,
+// wants reports whether the listener accepts eventType.
+func (l *listener) wants(eventType string) bool {
+	if l.events != nil {
+		if l.types == nil {
+			return true
+		}
+		_, ok := l.types[eventType]
+		return ok
 	}
-	go c.connectLoop()
-	return c
+	return l.eventType == "" || l.eventType == eventType
+}
+
+func (l *listener) close() {
+	if l.events != nil {
+		close(l.events)
+		return
+	}
+	close(l.ch)
+}
+
+// wsConn is the subset of *websocket.Conn used by connection, so a replayed
+// session can stand in for a live socket.
+type wsConn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	SetReadDeadline(t time.Time) error
+	Close() error
+}
+
+// newConnection creates and starts a connection to the given channel using
+// the client's hooks, recorder and dialer. shard is the market shard index.
+func (c *Client) newConnection(channel string, shard int) *connection {
+	ctx, cancel := context.WithCancel(c.parentCtx)
+	conn := &connection{
+		url:      c.endpoint + "/ws/" + channel,
+		reg:      newRegistry(channel),
+		ctx:      ctx,
+		cancel:   cancel,
+		hooks:    c.hooks,
+		cfg:      c.cfg,
+		recorder: c.recorder,
+		dial:     c.cfg.dialFunc(),
+		onError:  c.broadcastError,
+		shard:    shard,
+		onState:  c.states.publish,
+	}
+	if channel == ChannelUser {
+		conn.reg.authFn = c.authPayload
+	}
+	if c.replayer != nil {
+		conn.dial = replayDialer(c.replayer)
+	}
+	conn.setState(StateConnecting, nil)
+	go conn.connectLoop()
+	return conn
 }
 
 // connectLoop manages connect -> read -> reconnect cycle.
 func (c *connection) connectLoop() {
-	var attempt int
+	// connectLoop only returns once the connection is done for good.
+	defer c.setState(StateClosed, nil)
+
+	var attempt, failures int
+	connected := false
 	for {
 		if c.ctx.Err() != nil {
 			return
 		}
 
-		conn, _, err := websocket.DefaultDialer.DialContext(c.ctx, c.url, nil)
+		conn, err := c.dial(c.ctx, c.url)
 		if err != nil {
 			attempt++
+			failures++
+			if limit := c.cfg.backoff.MaxAttempts; limit > 0 && failures >= limit {
+				c.giveUp(err)
+				return
+			}
+			if connected {
+				c.setState(StateReconnecting, err)
+			} else {
+				c.setState(StateConnecting, err)
+			}
 			c.backoff(attempt)
 			continue
 		}
+		failures = 0
+		connected = true
 
 		c.connMu.Lock()
 		c.conn = conn
 		c.connMu.Unlock()
+		c.setState(StateConnected, nil)
+
+		if c.hooks != nil {
+			c.hooks.OnWSConnect(observe.WSConnect{URL: c.url, Attempt: attempt})
+		}
+		connectedAt := time.Now()
 
 		// Reset on successful connect
 		attempt = 0
@@ -140,7 +266,7 @@ func (c *connection) connectLoop() {
 		go c.heartbeatLoop(heartbeatCtx)
 
 		// Read messages until error (pass conn directly to avoid racy read of c.conn)
-		c.readLoop(conn)
+		readErr := c.readLoop(conn)
 
 		// Connection lost
 		heartbeatCancel()
@@ -149,31 +275,58 @@ func (c *connection) connectLoop() {
 		c.conn = nil
 		c.connMu.Unlock()
 
+		if c.hooks != nil {
+			c.hooks.OnWSDisconnect(observe.WSDisconnect{
+				URL:       c.url,
+				Uptime:    time.Since(connectedAt),
+				Err:       readErr,
+				Reconnect: c.ctx.Err() == nil,
+			})
+		}
+
 		if c.ctx.Err() != nil {
 			return
 		}
+		c.setState(StateReconnecting, readErr)
 		attempt++
 		c.backoff(attempt)
 	}
 }
 
-// readLoop reads messages from the WebSocket and dispatches to listeners.
-func (c *connection) readLoop(conn *websocket.Conn) {
+// readLoop reads messages from the WebSocket and dispatches to listeners. It
+// returns the read error that ended the connection.
+func (c *connection) readLoop(conn wsConn) error {
 	for {
+		if c.cfg.readTimeout > 0 {
+			if err := conn.SetReadDeadline(time.Now().Add(c.cfg.readTimeout)); err != nil {
+				return err
+			}
+		}
 		_, message, err := conn.ReadMessage()
 		if err != nil {
-			return
+			return err
 		}
 
 		text := string(message)
+		now := time.Now()
+		c.markMessage(now, text == "PONG")
 
 		// Handle PONG
 		if text == "PONG" {
 			c.pongMu.Lock()
-			c.lastPong = time.Now()
+			c.lastPong = now
 			c.pongMu.Unlock()
 			continue
 		}
+		if c.recorder != nil {
+			c.recorder.RecordWSFrame(c.url, replay.DirectionIn, message)
+		}
+		if ev, ok := parseErrorFrame(c.reg.channel, message); ok {
+			if c.onError != nil {
+				c.onError(ev)
+			}
+			continue
+		}
 
 		// Try to parse as array (batched messages)
 		c.dispatch(message)
@@ -182,26 +335,32 @@ func (c *connection) readLoop(conn *websocket.Conn) {
 
 // dispatch routes raw JSON to appropriate listeners.
 func (c *connection) dispatch(data []byte) {
-	// Check if it's an array
 	data = trimSpace(data)
-	if len(data) > 0 && data[0] == '[' {
-		var messages []json.RawMessage
-		if err := json.Unmarshal(data, &messages); err != nil {
-			return
-		}
-		for _, msg := range messages {
-			c.dispatchSingle(msg)
-		}
+	if len(data) == 0 || data[0] != '[' {
+		c.dispatchSingle(data)
 		return
 	}
-	c.dispatchSingle(data)
+	// Batched messages: split the array in place rather than decoding it.
+	s := scanner{b: data}
+	s.array(func() bool {
+		s.space()
+		start := s.i
+		if !s.skip() {
+			return false
+		}
+		c.dispatchSingle(data[start:s.i])
+		return true
+	})
 }
 
 func (c *connection) dispatchSingle(data []byte) {
-	var raw RawMessage
-	if err := json.Unmarshal(data, &raw); err != nil {
+	eventType, ok := sniffEventType(data)
+	if !ok {
 		return
 	}
+	if c.hooks != nil {
+		c.hooks.OnWSMessage(observe.WSMessage{URL: c.url, EventType: eventType, Size: len(data)})
+	}
 
 	c.listMu.Lock()
 	defer c.listMu.Unlock()
@@ -211,20 +370,39 @@ func (c *connection) dispatchSingle(data []byte) {
 		return
 	}
 
-	for _, l := range c.listeners {
-		if l.eventType == "" || l.eventType == raw.EventType {
+	// Typed listeners share one decoded event per frame.
+	var ev Event
+	decoded := false
+	for i := range c.listeners {
+		l := &c.listeners[i]
+		if !l.wants(eventType) {
+			continue
+		}
+		if l.events == nil {
 			select {
 			case l.ch <- json.RawMessage(data):
 			default:
 				// Drop if channel is full (slow consumer)
 			}
+			continue
+		}
+		if !decoded {
+			ev, _ = decodeEvent(eventType, data)
+			decoded = true
+		}
+		if ev == nil {
+			continue
+		}
+		select {
+		case l.events <- ev:
+		default:
 		}
 	}
 }
 
 // heartbeatLoop sends PING messages and checks for PONG responses.
 func (c *connection) heartbeatLoop(ctx context.Context) {
-	ticker := time.NewTicker(PingInterval)
+	ticker := time.NewTicker(c.cfg.pingInterval)
 	defer ticker.Stop()
 
 	for {
@@ -245,7 +423,7 @@ func (c *connection) heartbeatLoop(ctx context.Context) {
 			lastPong := c.lastPong
 			c.pongMu.Unlock()
 
-			if !lastPong.IsZero() && time.Since(lastPong) > PongTimeout {
+			if !lastPong.IsZero() && time.Since(lastPong) > c.cfg.pongTimeout {
 				// No PONG received within timeout, close connection to trigger reconnect
 				conn.Close()
 				return
@@ -258,44 +436,86 @@ func (c *connection) heartbeatLoop(ctx context.Context) {
 			if err != nil {
 				return
 			}
+			c.markPing(time.Now())
 		}
 	}
 }
 
-// subscribe adds a listener and sends the subscription request.
+// subscribe adds a listener and takes a reference on each ID in req for
+// eventType. A subscribe message is sent only for IDs that were not already
+// subscribed, or for every ID when the listener wants books; when ctx ends,
+// IDs nobody else needs are unsubscribed.
 func (c *connection) subscribe(ctx context.Context, req SubscriptionRequest, eventType string) <-chan json.RawMessage {
 	ch := make(chan json.RawMessage, channelBufferSize)
+	c.addListener(ctx, req, eventType, listener{eventType: eventType, ch: ch})
+	return ch
+}
+
+// subscribeEvents is subscribe for a typed listener receiving every event
+// type in types (nil = all).
+func (c *connection) subscribeEvents(ctx context.Context, req SubscriptionRequest, types map[string]struct{}) <-chan Event {
+	ch := make(chan Event, channelBufferSize)
+	c.addListener(ctx, req, "", listener{events: ch, types: types})
+	return ch
+}
+
+// addListener registers l, takes references on req's IDs for eventType and
+// releases both when ctx ends.
+func (c *connection) addListener(ctx context.Context, req SubscriptionRequest, eventType string, l listener) {
 	id := atomic.AddUint64(&c.nextID, 1)
+	l.id = id
 
 	c.listMu.Lock()
-	c.listeners = append(c.listeners, listener{id: id, eventType: eventType, ch: ch})
+	c.listeners = append(c.listeners, l)
 	c.listMu.Unlock()
 
-	// Track for reconnect
-	c.subsMu.Lock()
-	c.subs = append(c.subs, req)
-	c.subsMu.Unlock()
-
-	// Send subscription message
-	_ = c.sendJSON(req)
+	ids := c.reg.requestIDs(req)
+	send := c.reg.acquire(ids, eventType, req.Auth)
+	if c.reg.channel == ChannelMarket && l.wants(EventBook) {
+		// The server dumps books only on subscribe, so a book listener joining
+		// assets already subscribed re-sends it to get its snapshot. Existing
+		// book listeners see the dump as a resync.
+		send = ids
+	}
+	if len(send) > 0 {
+		// A send failure is recovered by resubscribe once connected.
+		if c.sendJSON(c.reg.request(OpSubscribe, send)) == nil {
+			c.markSubscribed()
+		}
+	}
 
 	go func() {
 		<-ctx.Done()
 		c.removeListener(id)
+		removed := c.reg.release(ids, eventType)
+		c.unsubscribe(removed)
+		if c.onIdle != nil && len(removed) > 0 {
+			c.onIdle(removed)
+		}
 	}()
+}
 
-	return ch
+// unsubscribe sends an unsubscribe message for ids, if any.
+func (c *connection) unsubscribe(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	req := c.reg.request(OpUnsubscribe, ids)
+	if req.empty() {
+		return nil
+	}
+	return c.sendJSON(req)
 }
 
-// resubscribe sends all tracked subscription requests (after reconnect).
+// resubscribe sends one compact request for every subscribed ID (after
+// reconnect).
 func (c *connection) resubscribe() {
-	c.subsMu.Lock()
-	subs := make([]SubscriptionRequest, len(c.subs))
-	copy(subs, c.subs)
-	c.subsMu.Unlock()
-
-	for _, sub := range subs {
-		c.sendJSON(sub)
+	ids := c.reg.active()
+	if len(ids) == 0 {
+		return
+	}
+	if c.sendJSON(c.reg.request(OpSubscribe, ids)) == nil {
+		c.markSubscribed()
 	}
 }
 
@@ -307,22 +527,22 @@ func (c *connection) sendJSON(v interface{}) error {
 	if conn == nil {
 		return fmt.Errorf("ws: not connected")
 	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("ws: encoding message: %w", err)
+	}
 	c.writeMu.Lock()
-	defer c.writeMu.Unlock()
-	return conn.WriteJSON(v)
+	err = conn.WriteMessage(websocket.TextMessage, data)
+	c.writeMu.Unlock()
+	if err == nil && c.recorder != nil {
+		c.recorder.RecordWSFrame(c.url, replay.DirectionOut, data)
+	}
+	return err
 }
 
 // backoff sleeps for an exponentially increasing duration with jitter.
 func (c *connection) backoff(attempt int) {
-	delay := float64(InitialBackoff) * math.Pow(BackoffMultiplier, float64(attempt-1))
-	if delay > float64(MaxBackoff) {
-		delay = float64(MaxBackoff)
-	}
-	// Jitter: [0.5, 1.5]
-	jitter := 0.5 + rand.Float64()
-	actual := time.Duration(delay * jitter)
-
-	timer := time.NewTimer(actual)
+	timer := time.NewTimer(c.cfg.backoff.delay(attempt))
 	defer timer.Stop()
 	select {
 	case <-c.ctx.Done():
@@ -330,6 +550,22 @@ func (c *connection) backoff(attempt int) {
 	}
 }
 
+// gaveUp reports whether the connection stopped after exhausting its backoff
+// policy.
+func (c *connection) gaveUp() bool {
+	return c.abandoned.Load()
+}
+
+// giveUp closes the connection after the backoff policy is exhausted.
+func (c *connection) giveUp(err error) {
+	c.abandoned.Store(true)
+	c.setState(StateClosed, err)
+	c.close()
+	if fn := c.cfg.backoff.OnGiveUp; fn != nil {
+		fn(c.url, err)
+	}
+}
+
 // close shuts down the connection.
 func (c *connection) close() {
 	c.cancel()
@@ -342,11 +578,13 @@ func (c *connection) close() {
 	// Mark closed before closing channels so dispatchSingle won't write to them.
 	c.listMu.Lock()
 	c.closed = true
-	for _, l := range c.listeners {
-		close(l.ch)
+	for i := range c.listeners {
+		c.listeners[i].close()
 	}
 	c.listeners = nil
 	c.listMu.Unlock()
+
+	c.setState(StateClosed, nil)
 }
 
 func (c *connection) removeListener(id uint64) {
@@ -359,60 +597,12 @@ func (c *connection) removeListener(id uint64) {
 		if l.id != id {
 			continue
 		}
-		close(l.ch)
+		l.close()
 		c.listeners = append(c.listeners[:i], c.listeners[i+1:]...)
 		return
 	}
 }
 
-// removeTrackedAssets removes subscriptions that match any of the given asset IDs.
-func (c *connection) removeTrackedAssets(assetIDs []string) {
-	remove := make(map[string]struct{}, len(assetIDs))
-	for _, id := range assetIDs {
-		remove[id] = struct{}{}
-	}
-	c.subsMu.Lock()
-	defer c.subsMu.Unlock()
-	filtered := c.subs[:0]
-	for _, sub := range c.subs {
-		keep := true
-		for _, id := range sub.AssetsIDs {
-			if _, ok := remove[id]; ok {
-				keep = false
-				break
-			}
-		}
-		if keep {
-			filtered = append(filtered, sub)
-		}
-	}
-	c.subs = filtered
-}
-
-// removeTrackedMarkets removes subscriptions that match any of the given market IDs.
-func (c *connection) removeTrackedMarkets(markets []string) {
-	remove := make(map[string]struct{}, len(markets))
-	for _, m := range markets {
-		remove[m] = struct{}{}
-	}
-	c.subsMu.Lock()
-	defer c.subsMu.Unlock()
-	filtered := c.subs[:0]
-	for _, sub := range c.subs {
-		keep := true
-		for _, m := range sub.Markets {
-			if _, ok := remove[m]; ok {
-				keep = false
-				break
-			}
-		}
-		if keep {
-			filtered = append(filtered, sub)
-		}
-	}
-	c.subs = filtered
-}
-
 func trimSpace(data []byte) []byte {
 	for len(data) > 0 && (data[0] == ' ' || data[0] == '\t' || data[0] == '\n' || data[0] == '\r') {
 		data = data[1:]
@@ -422,30 +612,19 @@ func trimSpace(data []byte) []byte {
 
 // --- Public API ---
 
-// getMarketConn lazily initializes the market channel connection.
-// By default the connection uses context.Background and lives until
-// Client.Close() is called. With WithConnectionContext, lifecycle follows the
-// configured context.
-func (c *Client) getMarketConn(_ context.Context) *connection {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	if c.marketConn == nil {
-		c.marketConn = newConnection(c.parentCtx, c.endpoint+"/ws/market")
-	}
-	return c.marketConn
-}
-
 // getUserConn lazily initializes the user channel connection.
 func (c *Client) getUserConn(_ context.Context) *connection {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if c.userConn == nil {
-		c.userConn = newConnection(c.parentCtx, c.endpoint+"/ws/user")
+	if c.userConn == nil || c.userConn.gaveUp() {
+		c.userConn = c.newConnection(ChannelUser, 0)
 	}
 	return c.userConn
 }
 
 // SubscribeOrderBook subscribes to orderbook updates for the given asset IDs.
+// The first update per asset is a full snapshot, also when another consumer
+// already follows the asset; that consumer then receives the snapshot again.
 func (c *Client) SubscribeOrderBook(ctx context.Context, assetIDs ...string) <-chan BookUpdate {
 	initialDump := true
 	req := SubscriptionRequest{
@@ -456,7 +635,7 @@ func (c *Client) SubscribeOrderBook(ctx context.Context, assetIDs ...string) <-c
 		InitialDump: &initialDump,
 	}
 
-	raw := c.getMarketConn(ctx).subscribe(ctx, req, EventBook)
+	raw := c.subscribeMarket(ctx, req, EventBook)
 	out := make(chan BookUpdate, channelBufferSize)
 	go func() {
 		defer close(out)
@@ -485,7 +664,7 @@ func (c *Client) SubscribePrices(ctx context.Context, assetIDs ...string) <-chan
 		InitialDump: &initialDump,
 	}
 
-	raw := c.getMarketConn(ctx).subscribe(ctx, req, EventPriceChange)
+	raw := c.subscribeMarket(ctx, req, EventPriceChange)
 	out := make(chan PriceChange, channelBufferSize)
 	go func() {
 		defer close(out)
@@ -514,7 +693,7 @@ func (c *Client) SubscribeLastTradePrice(ctx context.Context, assetIDs ...string
 		InitialDump: &initialDump,
 	}
 
-	raw := c.getMarketConn(ctx).subscribe(ctx, req, EventLastTradePrice)
+	raw := c.subscribeMarket(ctx, req, EventLastTradePrice)
 	out := make(chan LastTradePrice, channelBufferSize)
 	go func() {
 		defer close(out)
@@ -533,7 +712,9 @@ func (c *Client) SubscribeLastTradePrice(ctx context.Context, assetIDs ...string
 }
 
 // SubscribeOrders subscribes to order updates on the user channel.
-// Requires API credentials for authentication.
+// Requires API credentials for authentication. Credentials configured with
+// WithCreds or WithCredsFrom take precedence over the arguments, since the
+// user socket authenticates as a single account.
 func (c *Client) SubscribeOrders(ctx context.Context, apiKey, secret, passphrase string, markets ...string) <-chan OrderUpdate {
 	initialDump := true
 	req := SubscriptionRequest{
@@ -568,7 +749,9 @@ func (c *Client) SubscribeOrders(ctx context.Context, apiKey, secret, passphrase
 }
 
 // SubscribeTrades subscribes to trade updates on the user channel.
-// Requires API credentials for authentication.
+// Requires API credentials for authentication. Credentials configured with
+// WithCreds or WithCredsFrom take precedence over the arguments, since the
+// user socket authenticates as a single account.
 func (c *Client) SubscribeTrades(ctx context.Context, apiKey, secret, passphrase string, markets ...string) <-chan TradeUpdate {
 	initialDump := true
 	req := SubscriptionRequest{
@@ -613,7 +796,7 @@ func (c *Client) SubscribeTickSizeChange(ctx context.Context, assetIDs ...string
 		InitialDump: &initialDump,
 	}
 
-	raw := c.getMarketConn(ctx).subscribe(ctx, req, EventTickSizeChange)
+	raw := c.subscribeMarket(ctx, req, EventTickSizeChange)
 	out := make(chan TickSizeChange, channelBufferSize)
 	go func() {
 		defer close(out)
@@ -635,10 +818,14 @@ func (c *Client) SubscribeTickSizeChange(ctx context.Context, assetIDs ...string
 func (c *Client) Close() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if c.marketConn != nil {
-		c.marketConn.close()
-		c.marketConn = nil
-	}
+	for _, shard := range c.marketShards {
+		shard.close()
+	}
+	c.marketShards = nil
+	c.shardOf = nil
+	c.shardLoad = nil
+	c.errs.closeAll()
+	c.states.closeAll()
 	if c.userConn != nil {
 		c.userConn.close()
 		c.userConn = nil
@@ -2,8 +2,20 @@ package ws
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+	"github.com/lubluniky/clob-client-go/replay"
 )
 
 func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
@@ -18,17 +30,86 @@ func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
 	t.Fatalf("condition not met before timeout")
 }
 
+// installMarketConn makes conn the client's only market shard.
+func installMarketConn(client *Client, conn *connection) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.marketShards = []*connection{conn}
+	client.shardLoad = []int{0}
+	client.shardOf = make(map[string]int)
+}
+
+type tickSinkFunc func(tokenID, tickSize string) error
+
+func (f tickSinkFunc) ApplyTickSizeChange(tokenID, tickSize string) error {
+	return f(tokenID, tickSize)
+}
+
+func TestApplyTickSizeChangesReportsRejections(t *testing.T) {
+	client := NewClient()
+	connCtx, connCancel := context.WithCancel(context.Background())
+	defer connCancel()
+	conn := &connection{
+		ctx:    connCtx,
+		cancel: connCancel,
+		reg:    newRegistry(ChannelMarket),
+	}
+	installMarketConn(client, conn)
+
+	var mu sync.Mutex
+	var applied, rejected []string
+	sink := tickSinkFunc(func(_, tickSize string) error {
+		if tickSize == "bad" {
+			return errors.New("malformed tick size")
+		}
+		mu.Lock()
+		applied = append(applied, tickSize)
+		mu.Unlock()
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.ApplyTickSizeChanges(ctx, sink, func(ch TickSizeChange, err error) {
+			mu.Lock()
+			rejected = append(rejected, ch.AssetID+":"+err.Error())
+			mu.Unlock()
+		}, "1")
+	}()
+
+	waitFor(t, time.Second, func() bool {
+		conn.listMu.Lock()
+		defer conn.listMu.Unlock()
+		return len(conn.listeners) == 1
+	})
+	conn.dispatchSingle([]byte(`{"event_type":"tick_size_change","asset_id":"1","new_tick_size":"bad"}`))
+	conn.dispatchSingle([]byte(`{"event_type":"tick_size_change","asset_id":"1","new_tick_size":"0.001"}`))
+	waitFor(t, time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(applied) == 1
+	})
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(rejected) != 1 || rejected[0] != "1:malformed tick size" || applied[0] != "0.001" {
+		t.Fatalf("unexpected rejected=%v applied=%v", rejected, applied)
+	}
+}
+
 func TestSubscribeTickSizeChangeDispatchAndCleanup(t *testing.T) {
 	client := NewClient()
 	connCtx, connCancel := context.WithCancel(context.Background())
 	conn := &connection{
 		ctx:    connCtx,
 		cancel: connCancel,
+		reg:    newRegistry(ChannelMarket),
 	}
 
-	client.mu.Lock()
-	client.marketConn = conn
-	client.mu.Unlock()
+	installMarketConn(client, conn)
 
 	subCtx, subCancel := context.WithCancel(context.Background())
 	out := client.SubscribeTickSizeChange(subCtx, "1")
@@ -85,3 +166,622 @@ func TestWithConnectionContextControlsLifecycle(t *testing.T) {
 
 	client.Close()
 }
+
+func TestRecordAndReplayMarketFrames(t *testing.T) {
+	var buf strings.Builder
+	rec := replay.NewRecorderWriter(&buf)
+	url := DefaultEndpoint + "/ws/market"
+	rec.RecordWSFrame(url, replay.DirectionOut, []byte(`{"type":"market","assets_ids":["1"],"auth":{"apiKey":"k","secret":"s","passphrase":"p"}}`))
+	rec.RecordWSFrame(url, replay.DirectionIn, []byte(`{"event_type":"tick_size_change","asset_id":"1","market":"m","old_tick_size":"0.01","new_tick_size":"0.001","timestamp":"t"}`))
+	if strings.Contains(buf.String(), `"secret":"s"`) {
+		t.Fatalf("recording leaked subscription secret: %s", buf.String())
+	}
+
+	rp, err := replay.LoadReader(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	client := NewClient(WithEndpoint("ws://127.0.0.1:1"), WithReplay(rp))
+	defer client.Close()
+
+	out := client.SubscribeTickSizeChange(context.Background(), "1")
+	select {
+	case msg := <-out:
+		if msg.AssetID != "1" || msg.NewTickSize != "0.001" {
+			t.Fatalf("unexpected message: %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timeout waiting for replayed frame")
+	}
+}
+
+// fakeConn records writes and blocks reads until closed.
+type fakeConn struct {
+	mu     sync.Mutex
+	writes []SubscriptionRequest
+	done   chan struct{}
+}
+
+func (f *fakeConn) ReadMessage() (int, []byte, error) {
+	<-f.done
+	return 0, nil, errReplayClosed
+}
+
+func (f *fakeConn) WriteMessage(_ int, data []byte) error {
+	var req SubscriptionRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.writes = append(f.writes, req)
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeConn) SetReadDeadline(time.Time) error { return nil }
+
+func (f *fakeConn) Close() error { return nil }
+
+func (f *fakeConn) sent() []SubscriptionRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]SubscriptionRequest(nil), f.writes...)
+}
+
+func TestSubscriptionRefcounting(t *testing.T) {
+	fake := &fakeConn{done: make(chan struct{})}
+	connCtx, connCancel := context.WithCancel(context.Background())
+	defer connCancel()
+	conn := &connection{ctx: connCtx, cancel: connCancel, conn: fake, reg: newRegistry(ChannelMarket)}
+	client := NewClient()
+	installMarketConn(client, conn)
+
+	bookCtx, bookCancel := context.WithCancel(context.Background())
+	priceCtx, priceCancel := context.WithCancel(context.Background())
+	client.SubscribeOrderBook(bookCtx, "1", "2")
+	client.SubscribePrices(priceCtx, "1", "1")
+	client.SubscribeLastTradePrice(context.Background(), "3")
+
+	sent := fake.sent()
+	if len(sent) != 2 || strings.Join(sent[0].AssetsIDs, ",") != "1,2" || strings.Join(sent[1].AssetsIDs, ",") != "3" {
+		t.Fatalf("unexpected subscribe messages: %+v", sent)
+	}
+
+	// A second book consumer re-requests the snapshot of held assets.
+	secondBook, secondCancel := context.WithCancel(context.Background())
+	client.SubscribeOrderBook(secondBook, "2")
+	sent = fake.sent()
+	if len(sent) != 3 || strings.Join(sent[2].AssetsIDs, ",") != "2" || sent[2].InitialDump == nil || !*sent[2].InitialDump {
+		t.Fatalf("expected a snapshot request for 2, got %+v", sent)
+	}
+	secondCancel()
+	waitFor(t, time.Second, func() bool { return conn.reg.refs("2", EventBook) == 1 })
+	if conn.reg.refs("1", EventPriceChange) != 1 {
+		t.Fatalf("duplicate IDs in one call should count once")
+	}
+
+	bookCancel()
+	waitFor(t, time.Second, func() bool { return len(fake.sent()) == 4 })
+	if got := fake.sent()[3]; got.Operation != OpUnsubscribe || strings.Join(got.AssetsIDs, ",") != "2" {
+		t.Fatalf("expected unsubscribe of 2 only, got %+v", got)
+	}
+
+	if err := client.UnsubscribeMarket(context.Background(), "3"); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	conn.resubscribe()
+	sent = fake.sent()
+	last := sent[len(sent)-1]
+	if last.Operation != OpSubscribe || strings.Join(last.AssetsIDs, ",") != "1" || last.InitialDump == nil {
+		t.Fatalf("unexpected resubscribe: %+v", last)
+	}
+
+	priceCancel()
+	waitFor(t, time.Second, func() bool { return len(conn.reg.active()) == 0 })
+}
+
+func TestMarketShardingCapAndMergedStream(t *testing.T) {
+	rp, err := replay.LoadReader(strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	client := NewClient(WithReplay(rp), WithMaxAssetsPerConn(2))
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := client.SubscribeLastTradePrice(ctx, "1", "2", "3", "4", "5")
+	if got := client.MarketShards(); got != 3 {
+		t.Fatalf("expected 3 shards, got %d", got)
+	}
+	client.mu.Lock()
+	loads := append([]int(nil), client.shardLoad...)
+	last := client.marketShards[client.shardOf["5"]]
+	client.mu.Unlock()
+	if loads[0] != 2 || loads[1] != 2 || loads[2] != 1 {
+		t.Fatalf("unexpected shard loads: %v", loads)
+	}
+
+	last.dispatchSingle([]byte(`{"event_type":"last_trade_price","asset_id":"5","price":"0.5"}`))
+	select {
+	case msg := <-out:
+		if msg.AssetID != "5" {
+			t.Fatalf("unexpected message: %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timeout waiting for merged message")
+	}
+
+	// A second consumer reuses existing assignments.
+	client.SubscribeOrderBook(context.Background(), "5")
+	if got := client.MarketShards(); got != 3 {
+		t.Fatalf("expected no new shard, got %d", got)
+	}
+
+	cancel()
+	waitFor(t, time.Second, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return client.shardLoad[0] == 0 && client.shardLoad[1] == 0 && client.shardLoad[2] == 1
+	})
+	for range out {
+	}
+}
+
+func TestConfirmedSubscriptions(t *testing.T) {
+	var buf strings.Builder
+	rec := replay.NewRecorderWriter(&buf)
+	rec.RecordWSFrame(DefaultEndpoint+"/ws/user", replay.DirectionIn, []byte("Invalid api key, secret or passphrase"))
+	rec.RecordWSFrame(DefaultEndpoint+"/ws/market", replay.DirectionIn, []byte(`[{"event_type":"book","asset_id":"1","bids":[{"price":"0.4","size":"10"}],"asks":[]}]`))
+	rp, err := replay.LoadReader(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	client := NewClient(WithReplay(rp), WithSubscribeTimeout(200*time.Millisecond))
+	defer client.Close()
+	errs := client.SubscribeErrors(context.Background())
+
+	ctx := context.Background()
+	_, err = client.SubscribeOrdersConfirmed(ctx, "bad", "creds", "here")
+	var ev *ErrorEvent
+	if !errors.Is(err, ErrAuthFailed) || !errors.As(err, &ev) || ev.Channel != ChannelUser {
+		t.Fatalf("expected auth failure, got %v", err)
+	}
+	select {
+	case got := <-errs:
+		if !got.Auth {
+			t.Fatalf("expected typed auth failure event, got %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timeout waiting for error event")
+	}
+
+	books, err := client.SubscribeOrderBookConfirmed(ctx, "1")
+	if err != nil {
+		t.Fatalf("confirmed book subscription: %v", err)
+	}
+	if book := <-books; book.AssetID != "1" || len(book.Bids) != 1 {
+		t.Fatalf("unexpected first book: %+v", book)
+	}
+
+	if _, err := client.SubscribeLastTradePriceConfirmed(ctx, "2"); !errors.Is(err, ErrSubscribeTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+}
+
+func TestConfirmedSubscriptionIgnoresOtherAssetErrors(t *testing.T) {
+	client := NewClient(WithSubscribeTimeout(200 * time.Millisecond))
+	connCtx, connCancel := context.WithCancel(context.Background())
+	defer connCancel()
+	conn := &connection{
+		ctx:    connCtx,
+		cancel: connCancel,
+		reg:    newRegistry(ChannelMarket),
+	}
+	installMarketConn(client, conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	confirmed := func(assetID string, frames ...func()) error {
+		listeners := func() int {
+			conn.listMu.Lock()
+			defer conn.listMu.Unlock()
+			return len(conn.listeners)
+		}
+		before := listeners()
+		errc := make(chan error, 1)
+		go func() {
+			_, err := client.SubscribeLastTradePriceConfirmed(ctx, assetID)
+			errc <- err
+		}()
+		waitFor(t, time.Second, func() bool { return listeners() > before })
+		for _, frame := range frames {
+			frame()
+		}
+		return <-errc
+	}
+	fail := func(msg string) func() {
+		return func() { client.broadcastError(ErrorEvent{Channel: ChannelMarket, Message: msg}) }
+	}
+
+	// An error about another asset does not fail the subscription.
+	err := confirmed("111", fail("invalid asset 1111"), func() {
+		conn.dispatchSingle([]byte(`{"event_type":"last_trade_price","asset_id":"111","price":"0.5"}`))
+	})
+	if err != nil {
+		t.Fatalf("unrelated error failed the subscription: %v", err)
+	}
+
+	// An error naming the asset does.
+	var ev *ErrorEvent
+	if err := confirmed("222", fail("invalid asset 222")); !errors.As(err, &ev) || ev.Message != "invalid asset 222" {
+		t.Fatalf("expected the asset's error, got %v", err)
+	}
+
+	// An unattributed error is reported with the timeout.
+	if err := confirmed("333", fail("bad request")); !errors.Is(err, ErrSubscribeTimeout) || !errors.As(err, &ev) || ev.Message != "bad request" {
+		t.Fatalf("expected timeout with the unattributed error, got %v", err)
+	}
+}
+
+type countingHandler struct {
+	BaseHandler
+	order []string
+}
+
+func (h *countingHandler) OnBook(*BookUpdate)         { h.order = append(h.order, EventBook) }
+func (h *countingHandler) OnPriceChange(*PriceChange) { h.order = append(h.order, EventPriceChange) }
+
+func TestUnifiedSubscribeOrderingAndSharedDecode(t *testing.T) {
+	client := NewClient()
+	connCtx, connCancel := context.WithCancel(context.Background())
+	defer connCancel()
+	conn := &connection{ctx: connCtx, cancel: connCancel, reg: newRegistry(ChannelMarket)}
+	installMarketConn(client, conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	all := client.Subscribe(ctx, Topics{Assets: []string{"1"}})
+	books := client.Subscribe(ctx, Topics{Assets: []string{"1"}, EventTypes: []string{EventBook}})
+
+	conn.dispatch([]byte(`[{"event_type":"book","asset_id":"1"},{"event_type":"price_change","market":"m","price_changes":[{"asset_id":"1","price":"0.5","side":"BUY"}]}]`))
+
+	first, second := <-all, <-all
+	if _, ok := first.(*BookUpdate); !ok {
+		t.Fatalf("expected book first, got %T", first)
+	}
+	if pc, ok := second.(*PriceChange); !ok || pc.PriceChanges[0].Price != "0.5" {
+		t.Fatalf("expected price change second, got %#v", second)
+	}
+	if got := <-books; got != first {
+		t.Fatalf("expected the same decoded event to be shared")
+	}
+
+	h := &countingHandler{}
+	for _, ev := range []Event{first, second} {
+		Dispatch(h, ev)
+	}
+	if strings.Join(h.order, ",") != "book,price_change" {
+		t.Fatalf("unexpected handler order: %v", h.order)
+	}
+
+	cancel()
+	for range all {
+	}
+	select {
+	case ev, ok := <-books:
+		if ok {
+			t.Fatalf("unexpected event after filter: %T", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("filtered stream not closed")
+	}
+}
+
+func TestBackoffGiveUp(t *testing.T) {
+	gaveUp := make(chan string, 1)
+	client := NewClient(WithEndpoint("ws://127.0.0.1:1"), WithBackoff(BackoffPolicy{
+		Initial:     time.Millisecond,
+		Max:         time.Millisecond,
+		MaxAttempts: 2,
+		OnGiveUp:    func(url string, _ error) { gaveUp <- url },
+	}))
+	defer client.Close()
+
+	out := client.SubscribeOrderBook(context.Background(), "1")
+	select {
+	case url := <-gaveUp:
+		if url != "ws://127.0.0.1:1/ws/market" {
+			t.Fatalf("unexpected url: %s", url)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("backoff policy never gave up")
+	}
+	if _, ok := <-out; ok {
+		t.Fatalf("expected stream to close after give-up")
+	}
+
+	first := client.getMarketConn(context.Background())
+	if first.gaveUp() {
+		t.Fatalf("expected a fresh shard after give-up")
+	}
+}
+
+func TestDialerHeaderAndPingCadence(t *testing.T) {
+	var pings atomic.Int32
+	gotHeader := make(chan string, 1)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader <- r.Header.Get("X-Test")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(msg) == "PING" {
+				pings.Add(1)
+				_ = conn.WriteMessage(websocket.TextMessage, []byte("PONG"))
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(
+		WithEndpoint("ws"+strings.TrimPrefix(srv.URL, "http")),
+		WithDialer(&websocket.Dialer{HandshakeTimeout: time.Second}),
+		WithHeader(http.Header{"X-Test": []string{"yes"}}),
+		WithPingInterval(10*time.Millisecond),
+		WithReadTimeout(time.Second),
+		WithReadLimit(1<<20),
+	)
+	defer client.Close()
+	client.SubscribeOrderBook(context.Background(), "1")
+
+	select {
+	case h := <-gotHeader:
+		if h != "yes" {
+			t.Fatalf("handshake header mismatch: %q", h)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no handshake")
+	}
+	waitFor(t, 2*time.Second, func() bool { return pings.Load() >= 3 })
+}
+
+func TestStateChangesAcrossReconnect(t *testing.T) {
+	var conns atomic.Int32
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		first := conns.Add(1) == 1
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(msg) == "PING" {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte("PONG"))
+				continue
+			}
+			if first {
+				// Drop the first socket once it has subscribed.
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(
+		WithEndpoint("ws"+strings.TrimPrefix(srv.URL, "http")),
+		WithBackoff(BackoffPolicy{Initial: time.Millisecond, Max: 5 * time.Millisecond}),
+		WithPingInterval(10*time.Millisecond),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := client.StateChanges(ctx)
+	client.SubscribeOrderBook(ctx, "1")
+
+	want := []ConnState{StateConnecting, StateConnected, StateSubscribed, StateReconnecting, StateConnected, StateSubscribed}
+	for i, state := range want {
+		select {
+		case st := <-changes:
+			if st.State != state {
+				t.Fatalf("transition %d: got %s, want %s", i, st.State, state)
+			}
+			if st.Channel != ChannelMarket || st.Shard != 0 {
+				t.Fatalf("transition %d: unexpected origin %s/%d", i, st.Channel, st.Shard)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %s", state)
+		}
+	}
+
+	waitFor(t, 2*time.Second, func() bool {
+		states := client.State()
+		return len(states) == 1 && states[0].PongRTT > 0
+	})
+	st := client.State()[0]
+	if st.State != StateSubscribed || st.Reconnects != 1 || st.LastError == nil || st.LastMessage.IsZero() {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	client.Close()
+	select {
+	case st := <-changes:
+		if st.State != StateClosed {
+			t.Fatalf("expected closed, got %s", st.State)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no closed transition")
+	}
+	if _, ok := <-changes; ok {
+		t.Fatalf("state stream not closed with client")
+	}
+}
+
+func TestUserCredsSharedSubscriptionAndRotation(t *testing.T) {
+	clob := polymarket.NewClobClient(polymarket.WithCreds(polymarket.ApiCreds{ApiKey: "k1", ApiSecret: "s1", ApiPassphrase: "p1"}))
+	client := NewClient(WithCredsFrom(clob))
+
+	fake := &fakeConn{done: make(chan struct{})}
+	connCtx, connCancel := context.WithCancel(context.Background())
+	defer connCancel()
+	conn := &connection{ctx: connCtx, cancel: connCancel, conn: fake, reg: newRegistry(ChannelUser)}
+	conn.reg.authFn = client.authPayload
+	client.mu.Lock()
+	client.userConn = conn
+	client.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := client.SubscribeUser(ctx, "m1"); err != nil {
+		t.Fatalf("SubscribeUser: %v", err)
+	}
+	client.SubscribeOrders(ctx, "", "", "", "m1")
+
+	waitFor(t, time.Second, func() bool { return conn.reg.refs("m1", EventOrder) == 1 })
+	sent := fake.sent()
+	if len(sent) != 1 {
+		t.Fatalf("expected one shared subscription, got %+v", sent)
+	}
+	if sent[0].Auth == nil || sent[0].Auth.ApiKey != "k1" || len(sent[0].Markets) != 1 {
+		t.Fatalf("unexpected subscription: %+v", sent[0])
+	}
+
+	clob.SetApiCreds(polymarket.ApiCreds{ApiKey: "k2", ApiSecret: "s2", ApiPassphrase: "p2"})
+	conn.resubscribe()
+	sent = fake.sent()
+	if got := sent[len(sent)-1].Auth; got == nil || got.ApiKey != "k2" || got.Secret != "s2" || got.Passphrase != "p2" {
+		t.Fatalf("resubscribe did not use rotated creds: %+v", got)
+	}
+
+	if _, err := NewClient().SubscribeUser(ctx); !errors.Is(err, ErrNoCreds) {
+		t.Fatalf("expected ErrNoCreds, got %v", err)
+	}
+}
+
+var benchPriceChange = []byte(`{"market":"0x5f65177b394277fd294cd75650044e32ba009a95022d88a0c1d565897d72f8f1","price_changes":[` +
+	`{"asset_id":"71321045679252212594626385532706912750332728571942532289631379312455583992563","price":"0.535","size":"1250.5","side":"BUY","hash":"a1b2c3","best_bid":"0.535","best_ask":"0.54"},` +
+	`{"asset_id":"52114319501245915516055106046884209969926127482827954674443846427813813222426","price":"0.465","size":"0","side":"SELL","hash":"d4e5f6","best_bid":"0.46","best_ask":"0.465"}` +
+	`],"timestamp":"1757908892351","event_type":"price_change"}`)
+
+func TestFastPriceChangeMatchesStandardDecode(t *testing.T) {
+	var std PriceChange
+	if err := json.Unmarshal(benchPriceChange, &std); err != nil {
+		t.Fatal(err)
+	}
+	for name, frame := range map[string][]byte{
+		"fast": benchPriceChange,
+		// An escaped string forces the encoding/json fallback.
+		"fallback": []byte(strings.Replace(string(benchPriceChange), `"hash":"a1b2c3"`, `"hash":"a1b2\u0063"`, 1)),
+	} {
+		p := acquirePriceChange()
+		if !p.decode(frame) {
+			t.Fatalf("%s: decode failed", name)
+		}
+		if string(p.Market) != std.Market || p.Timestamp != 1757908892351 || len(p.Changes) != len(std.PriceChanges) {
+			t.Fatalf("%s: header mismatch: %s %d %d", name, p.Market, p.Timestamp, len(p.Changes))
+		}
+		for i, want := range std.PriceChanges {
+			got := p.Changes[i]
+			if string(got.AssetID) != want.AssetID || got.Side != want.Side ||
+				got.Price.String() != want.Price || got.Size.String() != want.Size ||
+				got.BestBid.String() != want.BestBid || got.BestAsk.String() != want.BestAsk {
+				t.Fatalf("%s: change %d mismatch: %+v vs %+v", name, i, got, want)
+			}
+		}
+		p.Release()
+	}
+
+	if typ, ok := sniffEventType(benchPriceChange); !ok || typ != EventPriceChange {
+		t.Fatalf("sniff: %q %v", typ, ok)
+	}
+	if _, ok := sniffEventType([]byte(`{"event_type":`)); ok {
+		t.Fatalf("sniff accepted a truncated frame")
+	}
+
+	for in, want := range map[string]Fixed{"0.535": 535000, "12": 12_000_000, "-0.01": -10000, ".5": 500000, "0.1234567": 123456} {
+		if got, err := ParseFixed(in); err != nil || got != want {
+			t.Fatalf("ParseFixed(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", ".", "1.2.3", "abc", "99999999999999999999"} {
+		if _, err := ParseFixed(in); err == nil {
+			t.Fatalf("ParseFixed(%q) accepted", in)
+		}
+	}
+}
+
+func TestDecodeFastPathDoesNotAllocate(t *testing.T) {
+	p := acquirePriceChange()
+	defer p.Release()
+	p.decode(benchPriceChange) // warm buffers
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, ok := sniffEventType(benchPriceChange); !ok {
+			t.Fatal("sniff failed")
+		}
+		if !p.decode(benchPriceChange) {
+			t.Fatal("decode failed")
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("fast path allocated %.0f times per frame", allocs)
+	}
+}
+
+// BenchmarkDecodePriceChange compares the event_type sniff plus decode done
+// per frame by SubscribePrices (encoding/json) and SubscribePricesFast.
+func BenchmarkDecodePriceChange(b *testing.B) {
+	b.Run("std", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(benchPriceChange)))
+		for i := 0; i < b.N; i++ {
+			var raw RawMessage
+			if err := json.Unmarshal(benchPriceChange, &raw); err != nil {
+				b.Fatal(err)
+			}
+			var update PriceChange
+			if err := json.Unmarshal(benchPriceChange, &update); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(benchPriceChange)))
+		for i := 0; i < b.N; i++ {
+			if _, ok := sniffEventType(benchPriceChange); !ok {
+				b.Fatal("sniff failed")
+			}
+			p := acquirePriceChange()
+			if !p.decode(benchPriceChange) {
+				b.Fatal("decode failed")
+			}
+			p.Release()
+		}
+	})
+}
+
+// BenchmarkSniffEventType compares reading event_type for routing.
+func BenchmarkSniffEventType(b *testing.B) {
+	b.Run("std", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var raw RawMessage
+			_ = json.Unmarshal(benchPriceChange, &raw)
+		}
+	})
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = sniffEventType(benchPriceChange)
+		}
+	})
+}
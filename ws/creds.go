@@ -0,0 +1,80 @@
+package ws
+
+import (
+	"context"
+	"errors"
+
+	polymarket "github.com/lubluniky/clob-client-go"
+)
+
+// ErrNoCreds is returned by SubscribeUser when the client has no credentials.
+var ErrNoCreds = errors.New("ws: no API credentials configured; use WithCreds or WithCredsFrom")
+
+// CredsSource supplies the current L2 API credentials. *polymarket.ClobClient
+// implements it, so a stream follows the REST client's SetApiCreds.
+type CredsSource interface {
+	ApiCreds() *polymarket.ApiCreds
+}
+
+type staticCreds polymarket.ApiCreds
+
+func (s staticCreds) ApiCreds() *polymarket.ApiCreds {
+	creds := polymarket.ApiCreds(s)
+	return &creds
+}
+
+// WithCreds authenticates the user channel with fixed credentials.
+func WithCreds(creds polymarket.ApiCreds) Option {
+	return func(c *Client) { c.creds = staticCreds(creds) }
+}
+
+// WithCredsFrom authenticates the user channel with whatever credentials src
+// holds when a subscription is sent, typically a *polymarket.ClobClient.
+// Rotated credentials are used from the next reconnect on.
+func WithCredsFrom(src CredsSource) Option {
+	return func(c *Client) { c.creds = src }
+}
+
+// Client receives rotated credentials from a polymarket.CredentialManager.
+var _ polymarket.CredsSetter = (*Client)(nil)
+
+// SetApiCreds replaces the user channel credentials. The open socket keeps
+// its authenticated session; the new credentials are sent on the next
+// subscribe or reconnect.
+func (c *Client) SetApiCreds(creds polymarket.ApiCreds) {
+	c.credsMu.Lock()
+	c.creds = staticCreds(creds)
+	c.credsMu.Unlock()
+}
+
+// authPayload returns the configured credentials as a subscription auth
+// block, or nil if none are configured.
+func (c *Client) authPayload() *AuthPayload {
+	c.credsMu.RLock()
+	src := c.creds
+	c.credsMu.RUnlock()
+	if src == nil {
+		return nil
+	}
+	creds := src.ApiCreds()
+	if creds == nil {
+		return nil
+	}
+	return &AuthPayload{
+		ApiKey:     creds.ApiKey,
+		Secret:     creds.ApiSecret,
+		Passphrase: creds.ApiPassphrase,
+	}
+}
+
+// SubscribeUser streams order and trade events for markets (condition IDs;
+// none means every market) using the client's credentials. Orders and trades
+// share one authenticated subscription, and events arrive in server order.
+//
+// The channel closes when ctx ends or the client is closed.
+func (c *Client) SubscribeUser(ctx context.Context, markets ...string) (<-chan Event, error) {
+	if c.authPayload() == nil {
+		return nil, ErrNoCreds
+	}
+	return c.Subscribe(ctx, Topics{User: true, Markets: markets}), nil
+}
@@ -0,0 +1,282 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSubscribeTimeout bounds how long the Subscribe*Confirmed variants
+// wait for the first message.
+const DefaultSubscribeTimeout = 10 * time.Second
+
+var (
+	// ErrAuthFailed matches (via errors.Is) error frames that report rejected
+	// API credentials on the user channel.
+	ErrAuthFailed = errors.New("ws: authentication failed")
+	// ErrSubscribeTimeout is returned when neither a message nor an error
+	// frame arrives within the subscribe timeout.
+	ErrSubscribeTimeout = errors.New("ws: timed out waiting for first subscription message")
+	// ErrSubscriptionClosed is returned when the stream closes before its
+	// first message, for example because the client was closed.
+	ErrSubscriptionClosed = errors.New("ws: subscription closed before first message")
+)
+
+// ErrorEvent is an error frame sent by the server instead of a market or user
+// event. It implements error.
+type ErrorEvent struct {
+	Channel string // ChannelMarket or ChannelUser
+	Message string
+	// Auth is true when the frame reports an authentication failure.
+	Auth bool
+	Time time.Time
+}
+
+func (e *ErrorEvent) Error() string {
+	return fmt.Sprintf("ws: %s channel error: %s", e.Channel, e.Message)
+}
+
+// Unwrap exposes ErrAuthFailed for authentication failures.
+func (e *ErrorEvent) Unwrap() error {
+	if e.Auth {
+		return ErrAuthFailed
+	}
+	return nil
+}
+
+// WithSubscribeTimeout sets how long the Subscribe*Confirmed variants wait
+// for the first message. Default DefaultSubscribeTimeout.
+func WithSubscribeTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.subscribeTimeout = d
+		}
+	}
+}
+
+// SubscribeErrors streams server error frames from every channel, including
+// typed authentication failures (Auth set). The channel closes when ctx ends
+// or the client is closed. Slow consumers miss events rather than stalling
+// the read loop.
+func (c *Client) SubscribeErrors(ctx context.Context) <-chan ErrorEvent {
+	ch, stop := c.watchErrors()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ch
+}
+
+// watchErrors registers an error subscriber and returns a function that
+// unregisters and closes it.
+func (c *Client) watchErrors() (<-chan ErrorEvent, func()) {
+	return c.errs.subscribe()
+}
+
+// broadcastError fans an error frame out to subscribers without blocking.
+func (c *Client) broadcastError(ev ErrorEvent) {
+	c.errs.publish(ev)
+}
+
+// parseErrorFrame reports whether data is an error frame rather than an event:
+// plain text other than heartbeats, or a JSON object without event_type that
+// carries an error or message field.
+func parseErrorFrame(channel string, data []byte) (ErrorEvent, bool) {
+	data = trimSpace(data)
+	if len(data) == 0 || data[0] == '[' {
+		return ErrorEvent{}, false
+	}
+	var msg string
+	if data[0] == '{' {
+		var frame struct {
+			EventType string `json:"event_type"`
+			Error     string `json:"error"`
+			Message   string `json:"message"`
+		}
+		if json.Unmarshal(data, &frame) != nil || frame.EventType != "" {
+			return ErrorEvent{}, false
+		}
+		msg = frame.Error
+		if msg == "" {
+			msg = frame.Message
+		}
+		if msg == "" {
+			return ErrorEvent{}, false
+		}
+	} else {
+		msg = strings.TrimSpace(string(data))
+		if msg == "PONG" || msg == "PING" {
+			return ErrorEvent{}, false
+		}
+	}
+	return ErrorEvent{
+		Channel: channel,
+		Message: msg,
+		Auth:    channel == ChannelUser && isAuthMessage(msg),
+		Time:    time.Now(),
+	}, true
+}
+
+func isAuthMessage(msg string) bool {
+	lower := strings.ToLower(msg)
+	for _, marker := range []string{"auth", "unauthorized", "forbidden", "api key", "apikey", "credential", "passphrase"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// Confirmed subscriptions
+// ---------------------------------------------------------------------------
+
+// confirm starts a subscription and waits for its first message, an error
+// frame for it, or the subscribe timeout. On failure the subscription is
+// released. On success the first message is re-delivered on the returned
+// stream.
+//
+// Error frames do not say which request they answer. With no assetIDs, as on
+// the user channel, every error frame on channel fails the subscription;
+// otherwise only frames naming one of assetIDs do. An unattributed frame is
+// joined to ErrSubscribeTimeout if the wait then times out.
+func confirm[T any](ctx context.Context, c *Client, channel string, assetIDs []string, subscribe func(context.Context) <-chan T) (<-chan T, error) {
+	errs, stopErrs := c.watchErrors()
+	defer stopErrs()
+
+	subCtx, cancel := context.WithCancel(ctx)
+	in := subscribe(subCtx)
+
+	timer := time.NewTimer(c.subscribeTimeout)
+	defer timer.Stop()
+
+	var unattributed *ErrorEvent
+	for {
+		select {
+		case first, ok := <-in:
+			if !ok {
+				cancel()
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				return nil, ErrSubscriptionClosed
+			}
+			out := make(chan T, channelBufferSize)
+			out <- first
+			go func() {
+				defer close(out)
+				defer cancel()
+				for msg := range in {
+					select {
+					case out <- msg:
+					case <-subCtx.Done():
+					}
+				}
+			}()
+			return out, nil
+		case ev, ok := <-errs:
+			if !ok {
+				cancel()
+				return nil, ErrSubscriptionClosed
+			}
+			if ev.Channel != channel {
+				continue
+			}
+			if len(assetIDs) > 0 && !mentionsAny(ev.Message, assetIDs) {
+				unattributed = &ev
+				continue
+			}
+			cancel()
+			return nil, &ev
+		case <-timer.C:
+			cancel()
+			if unattributed != nil {
+				return nil, errors.Join(ErrSubscribeTimeout, unattributed)
+			}
+			return nil, ErrSubscribeTimeout
+		case <-ctx.Done():
+			cancel()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// mentionsAny reports whether msg names one of ids as a whole word, so "1"
+// does not match "10".
+func mentionsAny(msg string, ids []string) bool {
+	isWord := func(b byte) bool {
+		return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+	}
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		for rest, off := msg, 0; ; {
+			i := strings.Index(rest, id)
+			if i < 0 {
+				break
+			}
+			start, end := off+i, off+i+len(id)
+			if (start == 0 || !isWord(msg[start-1])) && (end == len(msg) || !isWord(msg[end])) {
+				return true
+			}
+			rest, off = msg[start+1:], start+1
+		}
+	}
+	return false
+}
+
+// SubscribeOrderBookConfirmed is SubscribeOrderBook that waits for the first
+// update or an error frame naming one of assetIDs. Error frames that name no
+// requested asset cannot be attributed to this request and do not fail it.
+func (c *Client) SubscribeOrderBookConfirmed(ctx context.Context, assetIDs ...string) (<-chan BookUpdate, error) {
+	return confirm(ctx, c, ChannelMarket, assetIDs, func(ctx context.Context) <-chan BookUpdate {
+		return c.SubscribeOrderBook(ctx, assetIDs...)
+	})
+}
+
+// SubscribePricesConfirmed is SubscribePrices that waits for the first event
+// or an error frame naming one of assetIDs.
+func (c *Client) SubscribePricesConfirmed(ctx context.Context, assetIDs ...string) (<-chan PriceChange, error) {
+	return confirm(ctx, c, ChannelMarket, assetIDs, func(ctx context.Context) <-chan PriceChange {
+		return c.SubscribePrices(ctx, assetIDs...)
+	})
+}
+
+// SubscribeLastTradePriceConfirmed is SubscribeLastTradePrice that waits for
+// the first event or an error frame naming one of assetIDs.
+func (c *Client) SubscribeLastTradePriceConfirmed(ctx context.Context, assetIDs ...string) (<-chan LastTradePrice, error) {
+	return confirm(ctx, c, ChannelMarket, assetIDs, func(ctx context.Context) <-chan LastTradePrice {
+		return c.SubscribeLastTradePrice(ctx, assetIDs...)
+	})
+}
+
+// SubscribeTickSizeChangeConfirmed is SubscribeTickSizeChange that waits for
+// the first event or an error frame naming one of assetIDs. Tick size changes are rare, so use a
+// long subscribe timeout.
+func (c *Client) SubscribeTickSizeChangeConfirmed(ctx context.Context, assetIDs ...string) (<-chan TickSizeChange, error) {
+	return confirm(ctx, c, ChannelMarket, assetIDs, func(ctx context.Context) <-chan TickSizeChange {
+		return c.SubscribeTickSizeChange(ctx, assetIDs...)
+	})
+}
+
+// SubscribeOrdersConfirmed is SubscribeOrders that waits for the first update
+// or an error frame. Rejected credentials return an *ErrorEvent matching
+// ErrAuthFailed.
+func (c *Client) SubscribeOrdersConfirmed(ctx context.Context, apiKey, secret, passphrase string, markets ...string) (<-chan OrderUpdate, error) {
+	return confirm(ctx, c, ChannelUser, nil, func(ctx context.Context) <-chan OrderUpdate {
+		return c.SubscribeOrders(ctx, apiKey, secret, passphrase, markets...)
+	})
+}
+
+// SubscribeTradesConfirmed is SubscribeTrades that waits for the first update
+// or an error frame. Rejected credentials return an *ErrorEvent matching
+// ErrAuthFailed.
+func (c *Client) SubscribeTradesConfirmed(ctx context.Context, apiKey, secret, passphrase string, markets ...string) (<-chan TradeUpdate, error) {
+	return confirm(ctx, c, ChannelUser, nil, func(ctx context.Context) <-chan TradeUpdate {
+		return c.SubscribeTrades(ctx, apiKey, secret, passphrase, markets...)
+	})
+}
@@ -0,0 +1,164 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Event is one decoded WebSocket event. It is a closed union of
+// *BookUpdate, *PriceChange, *TickSizeChange, *LastTradePrice, *OrderUpdate
+// and *TradeUpdate; use a type switch to consume it. Events are shared
+// between subscribers and must be treated as read-only.
+type Event interface {
+	EventType() string
+	event()
+}
+
+func (*BookUpdate) EventType() string     { return EventBook }
+func (*PriceChange) EventType() string    { return EventPriceChange }
+func (*TickSizeChange) EventType() string { return EventTickSizeChange }
+func (*LastTradePrice) EventType() string { return EventLastTradePrice }
+func (*OrderUpdate) EventType() string    { return EventOrder }
+func (*TradeUpdate) EventType() string    { return EventTrade }
+
+func (*BookUpdate) event()     {}
+func (*PriceChange) event()    {}
+func (*TickSizeChange) event() {}
+func (*LastTradePrice) event() {}
+func (*OrderUpdate) event()    {}
+func (*TradeUpdate) event()    {}
+
+// decodeEvent decodes a single event frame of the given type. Unknown event
+// types return nil without error.
+func decodeEvent(eventType string, data []byte) (Event, error) {
+	var ev Event
+	switch eventType {
+	case EventBook:
+		ev = new(BookUpdate)
+	case EventPriceChange:
+		ev = new(PriceChange)
+	case EventTickSizeChange:
+		ev = new(TickSizeChange)
+	case EventLastTradePrice:
+		ev = new(LastTradePrice)
+	case EventOrder:
+		ev = new(OrderUpdate)
+	case EventTrade:
+		ev = new(TradeUpdate)
+	default:
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, ev); err != nil {
+		return nil, fmt.Errorf("ws: decoding %s event: %w", eventType, err)
+	}
+	return ev, nil
+}
+
+// Topics selects what Subscribe streams.
+type Topics struct {
+	// Assets subscribes the market channel for these asset (token) IDs.
+	Assets []string
+	// Auth subscribes the user channel, limited to Markets (condition IDs)
+	// when set and covering every market otherwise.
+	Auth *AuthPayload
+	// User subscribes the user channel with the client's own credentials
+	// (WithCreds or WithCredsFrom) instead of Auth.
+	User    bool
+	Markets []string
+	// EventTypes filters the stream to these event types (Event* constants).
+	// Empty means all.
+	EventTypes []string
+}
+
+// Subscribe streams every event for topics on one channel, decoded once per
+// frame regardless of how many subscribers share it. Events from one socket
+// arrive in the order the server sent them, so a book and a price_change for
+// the same asset are never reordered. Market and user events come from
+// different sockets and are interleaved as received.
+//
+// The channel closes when ctx ends or the client is closed.
+func (c *Client) Subscribe(ctx context.Context, topics Topics) <-chan Event {
+	var types map[string]struct{}
+	if len(topics.EventTypes) > 0 {
+		types = make(map[string]struct{}, len(topics.EventTypes))
+		for _, t := range topics.EventTypes {
+			types[t] = struct{}{}
+		}
+	}
+
+	var streams []<-chan Event
+	if len(topics.Assets) > 0 {
+		req := SubscriptionRequest{Type: ChannelMarket, Operation: OpSubscribe, AssetsIDs: topics.Assets, Markets: []string{}}
+		streams = append(streams, subscribeSharded(c, ctx, req, func(conn *connection, part SubscriptionRequest) <-chan Event {
+			return conn.subscribeEvents(ctx, part, types)
+		}))
+	}
+	if topics.Auth != nil || topics.User {
+		req := SubscriptionRequest{Type: ChannelUser, Operation: OpSubscribe, AssetsIDs: []string{}, Markets: topics.Markets, Auth: topics.Auth}
+		streams = append(streams, c.getUserConn(ctx).subscribeEvents(ctx, req, types))
+	}
+
+	switch len(streams) {
+	case 0:
+		out := make(chan Event)
+		close(out)
+		return out
+	case 1:
+		return streams[0]
+	default:
+		return mergeStreams(ctx, streams)
+	}
+}
+
+// Handler receives events by type. Embed BaseHandler to implement only the
+// callbacks you need.
+type Handler interface {
+	OnBook(*BookUpdate)
+	OnPriceChange(*PriceChange)
+	OnTickSizeChange(*TickSizeChange)
+	OnLastTradePrice(*LastTradePrice)
+	OnOrder(*OrderUpdate)
+	OnTrade(*TradeUpdate)
+}
+
+// BaseHandler is a Handler whose callbacks do nothing.
+type BaseHandler struct{}
+
+func (BaseHandler) OnBook(*BookUpdate)               {}
+func (BaseHandler) OnPriceChange(*PriceChange)       {}
+func (BaseHandler) OnTickSizeChange(*TickSizeChange) {}
+func (BaseHandler) OnLastTradePrice(*LastTradePrice) {}
+func (BaseHandler) OnOrder(*OrderUpdate)             {}
+func (BaseHandler) OnTrade(*TradeUpdate)             {}
+
+// Dispatch calls the Handler callback matching ev.
+func Dispatch(h Handler, ev Event) {
+	switch e := ev.(type) {
+	case *BookUpdate:
+		h.OnBook(e)
+	case *PriceChange:
+		h.OnPriceChange(e)
+	case *TickSizeChange:
+		h.OnTickSizeChange(e)
+	case *LastTradePrice:
+		h.OnLastTradePrice(e)
+	case *OrderUpdate:
+		h.OnOrder(e)
+	case *TradeUpdate:
+		h.OnTrade(e)
+	}
+}
+
+// Handle subscribes to topics and invokes h serially, in arrival order, until
+// ctx ends (returning ctx.Err()) or the client is closed (returning nil).
+func (c *Client) Handle(ctx context.Context, topics Topics, h Handler) error {
+	if h == nil {
+		return errors.New("ws: nil handler")
+	}
+	for ev := range c.Subscribe(ctx, topics) {
+		Dispatch(h, ev)
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,228 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// FastPriceChange is a price_change event decoded without allocation into a
+// pooled object. Byte slices point into buffers owned by the object, so it is
+// only valid until Release.
+type FastPriceChange struct {
+	Market []byte
+	// Timestamp is the server time in Unix milliseconds.
+	Timestamp int64
+	Changes   []FastPriceLevel
+}
+
+// FastPriceLevel is one asset's change within a FastPriceChange.
+type FastPriceLevel struct {
+	AssetID []byte
+	Side    string // "BUY" or "SELL"
+	Price   Fixed
+	// Size is the new aggregate size at Price; zero removes the level.
+	Size    Fixed
+	BestBid Fixed
+	BestAsk Fixed
+	Hash    []byte
+}
+
+var priceChangePool = sync.Pool{New: func() any { return new(FastPriceChange) }}
+
+func acquirePriceChange() *FastPriceChange {
+	return priceChangePool.Get().(*FastPriceChange)
+}
+
+// Release returns p to the pool. p must not be used afterwards.
+func (p *FastPriceChange) Release() {
+	p.reset()
+	priceChangePool.Put(p)
+}
+
+func (p *FastPriceChange) reset() {
+	p.Market = p.Market[:0]
+	p.Timestamp = 0
+	p.Changes = p.Changes[:0]
+}
+
+// nextLevel appends a cleared level, reusing the buffers of a previous use.
+func (p *FastPriceChange) nextLevel() *FastPriceLevel {
+	n := len(p.Changes)
+	if n < cap(p.Changes) {
+		p.Changes = p.Changes[:n+1]
+	} else {
+		p.Changes = append(p.Changes, FastPriceLevel{})
+	}
+	l := &p.Changes[n]
+	*l = FastPriceLevel{AssetID: l.AssetID[:0], Hash: l.Hash[:0]}
+	return l
+}
+
+// decode fills p from a price_change frame, falling back to encoding/json
+// for frames the scanner does not handle (escaped strings, numeric fields).
+func (p *FastPriceChange) decode(data []byte) bool {
+	if p.decodeFast(data) {
+		return true
+	}
+	var slow PriceChange
+	if json.Unmarshal(data, &slow) != nil {
+		return false
+	}
+	return p.fromPriceChange(&slow)
+}
+
+func (p *FastPriceChange) decodeFast(data []byte) bool {
+	p.reset()
+	s := scanner{b: data}
+	return s.object(func(key []byte) bool {
+		switch string(key) {
+		case "market":
+			v, ok := s.str()
+			p.Market = append(p.Market, v...)
+			return ok
+		case "timestamp":
+			v, ok := s.str()
+			if !ok {
+				return false
+			}
+			p.Timestamp, ok = parseMillis(v)
+			return ok
+		case "price_changes":
+			return s.array(func() bool { return p.decodeLevel(&s) })
+		default:
+			return s.skip()
+		}
+	})
+}
+
+func (p *FastPriceChange) decodeLevel(s *scanner) bool {
+	l := p.nextLevel()
+	return s.object(func(key []byte) bool {
+		var dst *Fixed
+		switch string(key) {
+		case "asset_id":
+			v, ok := s.str()
+			l.AssetID = append(l.AssetID, v...)
+			return ok
+		case "hash":
+			v, ok := s.str()
+			l.Hash = append(l.Hash, v...)
+			return ok
+		case "side":
+			v, ok := s.str()
+			l.Side = internSide(v)
+			return ok
+		case "price":
+			dst = &l.Price
+		case "size":
+			dst = &l.Size
+		case "best_bid":
+			dst = &l.BestBid
+		case "best_ask":
+			dst = &l.BestAsk
+		default:
+			return s.skip()
+		}
+		v, ok := s.str()
+		if !ok {
+			return false
+		}
+		*dst, ok = parseOptionalFixed(v)
+		return ok
+	})
+}
+
+// fromPriceChange converts a conventionally decoded event.
+func (p *FastPriceChange) fromPriceChange(pc *PriceChange) bool {
+	p.reset()
+	p.Market = append(p.Market, pc.Market...)
+	var ok bool
+	if p.Timestamp, ok = parseMillis([]byte(pc.Timestamp)); !ok {
+		return false
+	}
+	for _, e := range pc.PriceChanges {
+		l := p.nextLevel()
+		l.AssetID = append(l.AssetID, e.AssetID...)
+		l.Hash = append(l.Hash, e.Hash...)
+		l.Side = internSide([]byte(e.Side))
+		for _, f := range []struct {
+			dst *Fixed
+			src string
+		}{{&l.Price, e.Price}, {&l.Size, e.Size}, {&l.BestBid, e.BestBid}, {&l.BestAsk, e.BestAsk}} {
+			if *f.dst, ok = parseOptionalFixed([]byte(f.src)); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseOptionalFixed parses v, treating an empty string as zero.
+func parseOptionalFixed(v []byte) (Fixed, bool) {
+	if len(v) == 0 {
+		return 0, true
+	}
+	return parseFixed(v)
+}
+
+// parseMillis parses a decimal timestamp; empty is zero.
+func parseMillis(v []byte) (int64, bool) {
+	var n int64
+	for _, c := range v {
+		if c < '0' || c > '9' || n > (1<<62)/10 {
+			return 0, false
+		}
+		n = n*10 + int64(c-'0')
+	}
+	return n, true
+}
+
+func internSide(b []byte) string {
+	switch string(b) {
+	case "BUY":
+		return "BUY"
+	case "SELL":
+		return "SELL"
+	case "":
+		return ""
+	}
+	return string(b)
+}
+
+// SubscribePricesFast is SubscribePrices on the zero-allocation path: frames
+// are decoded by a hand-written scanner into pooled FastPriceChange values
+// with fixed-point prices. Call Release on each value once it has been
+// handled; values not released are garbage collected normally.
+//
+// The channel closes when ctx ends or the client is closed.
+func (c *Client) SubscribePricesFast(ctx context.Context, assetIDs ...string) <-chan *FastPriceChange {
+	initialDump := true
+	req := SubscriptionRequest{
+		Type:        ChannelMarket,
+		Operation:   OpSubscribe,
+		AssetsIDs:   assetIDs,
+		Markets:     []string{},
+		InitialDump: &initialDump,
+	}
+
+	raw := c.subscribeMarket(ctx, req, EventPriceChange)
+	out := make(chan *FastPriceChange, channelBufferSize)
+	go func() {
+		defer close(out)
+		for msg := range raw {
+			update := acquirePriceChange()
+			if !update.decode(msg) {
+				update.Release()
+				continue
+			}
+			select {
+			case out <- update:
+			case <-ctx.Done():
+				update.Release()
+				return
+			}
+		}
+	}()
+	return out
+}
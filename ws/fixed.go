@@ -0,0 +1,96 @@
+package ws
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FixedDecimals is the number of fractional digits carried by Fixed, enough
+// for every price and size the CLOB quotes (USDC has six decimals).
+const FixedDecimals = 6
+
+const fixedScale = 1_000_000
+
+// Fixed is a decimal number stored as an integer count of 10^-6 units, so
+// prices and sizes can be compared and summed without float rounding or
+// string parsing on the hot path.
+type Fixed int64
+
+// ParseFixed parses a decimal string such as "0.535" or "-12". Digits beyond
+// FixedDecimals are truncated.
+func ParseFixed(s string) (Fixed, error) {
+	f, ok := parseFixed([]byte(s))
+	if !ok {
+		return 0, fmt.Errorf("ws: invalid decimal %q", s)
+	}
+	return f, nil
+}
+
+// parseFixed parses b without allocating.
+func parseFixed(b []byte) (Fixed, bool) {
+	neg := false
+	if len(b) > 0 && (b[0] == '-' || b[0] == '+') {
+		neg = b[0] == '-'
+		b = b[1:]
+	}
+	var n int64
+	frac, digits := -1, 0
+	for _, c := range b {
+		switch {
+		case c == '.':
+			if frac >= 0 {
+				return 0, false
+			}
+			frac = 0
+		case c >= '0' && c <= '9':
+			digits++
+			if frac >= FixedDecimals {
+				continue
+			}
+			if n > (math.MaxInt64-9)/10 {
+				return 0, false
+			}
+			n = n*10 + int64(c-'0')
+			if frac >= 0 {
+				frac++
+			}
+		default:
+			return 0, false
+		}
+	}
+	if digits == 0 {
+		return 0, false
+	}
+	for frac = max(frac, 0); frac < FixedDecimals; frac++ {
+		if n > math.MaxInt64/10 {
+			return 0, false
+		}
+		n *= 10
+	}
+	if neg {
+		n = -n
+	}
+	return Fixed(n), true
+}
+
+// Float64 returns f as a float64.
+func (f Fixed) Float64() float64 {
+	return float64(f) / fixedScale
+}
+
+// String formats f without trailing zeros, for example "0.535".
+func (f Fixed) String() string {
+	u := uint64(f)
+	sign := ""
+	if f < 0 {
+		u = uint64(-f)
+		sign = "-"
+	}
+	s := sign + strconv.FormatUint(u/fixedScale, 10)
+	if frac := u % fixedScale; frac != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%06d", frac), "0")
+	}
+	return s
+}
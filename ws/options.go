@@ -0,0 +1,157 @@
+package ws
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// BackoffPolicy controls reconnection after a failed dial or a dropped
+// connection.
+type BackoffPolicy struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// MaxAttempts is the number of consecutive failed dials after which the
+	// connection gives up. Zero means retry forever.
+	MaxAttempts int
+	// OnGiveUp is called once when MaxAttempts is exhausted, with the channel
+	// URL and the last dial error. The connection's subscription channels are
+	// closed; later subscriptions open a fresh connection.
+	OnGiveUp func(url string, err error)
+}
+
+// DefaultBackoffPolicy is the policy used unless WithBackoff is given.
+var DefaultBackoffPolicy = BackoffPolicy{
+	Initial:    InitialBackoff,
+	Max:        MaxBackoff,
+	Multiplier: BackoffMultiplier,
+}
+
+// delay returns the sleep before the given attempt (1-based), with jitter in
+// [0.5, 1.5).
+func (p BackoffPolicy) delay(attempt int) time.Duration {
+	d := float64(p.Initial) * math.Pow(p.Multiplier, float64(attempt-1))
+	if d > float64(p.Max) {
+		d = float64(p.Max)
+	}
+	return time.Duration(d * (0.5 + rand.Float64()))
+}
+
+// connConfig holds per-connection tuning shared by every socket of a Client.
+type connConfig struct {
+	backoff      BackoffPolicy
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+	readTimeout  time.Duration
+	dialer       *websocket.Dialer
+	header       http.Header
+	compression  bool
+	readLimit    int64
+}
+
+func defaultConnConfig() connConfig {
+	return connConfig{
+		backoff:      DefaultBackoffPolicy,
+		pingInterval: PingInterval,
+		pongTimeout:  PongTimeout,
+		dialer:       websocket.DefaultDialer,
+	}
+}
+
+// dialFunc returns a dial function applying the dialer, headers, compression
+// and read limit.
+func (cfg connConfig) dialFunc() func(context.Context, string) (wsConn, error) {
+	dialer := cfg.dialer
+	if cfg.compression && !dialer.EnableCompression {
+		d := *dialer
+		d.EnableCompression = true
+		dialer = &d
+	}
+	return func(ctx context.Context, url string) (wsConn, error) {
+		conn, _, err := dialer.DialContext(ctx, url, cfg.header)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.readLimit > 0 {
+			conn.SetReadLimit(cfg.readLimit)
+		}
+		if cfg.compression {
+			conn.EnableWriteCompression(true)
+		}
+		return conn, nil
+	}
+}
+
+// WithBackoff replaces the reconnect backoff policy. Zero Initial, Max or
+// Multiplier fields keep their defaults.
+func WithBackoff(p BackoffPolicy) Option {
+	return func(c *Client) {
+		if p.Initial <= 0 {
+			p.Initial = InitialBackoff
+		}
+		if p.Max <= 0 {
+			p.Max = MaxBackoff
+		}
+		if p.Multiplier < 1 {
+			p.Multiplier = BackoffMultiplier
+		}
+		c.cfg.backoff = p
+	}
+}
+
+// WithPingInterval sets how often PING is sent. Default PingInterval.
+func WithPingInterval(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.cfg.pingInterval = d
+		}
+	}
+}
+
+// WithPongTimeout sets how long without a PONG before the connection is
+// considered dead and re-dialed. Default PongTimeout.
+func WithPongTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.cfg.pongTimeout = d
+		}
+	}
+}
+
+// WithReadTimeout sets a read deadline renewed before every read, so a socket
+// that goes silent (no data and no PONG) is dropped after d. It should exceed
+// the ping interval. Disabled by default.
+func WithReadTimeout(d time.Duration) Option {
+	return func(c *Client) { c.cfg.readTimeout = d }
+}
+
+// WithDialer sets the websocket.Dialer, for example to configure a proxy,
+// TLS settings or handshake timeout. Default websocket.DefaultDialer.
+func WithDialer(d *websocket.Dialer) Option {
+	return func(c *Client) {
+		if d != nil {
+			c.cfg.dialer = d
+		}
+	}
+}
+
+// WithHeader sets extra HTTP headers sent with the WebSocket handshake.
+func WithHeader(h http.Header) Option {
+	return func(c *Client) { c.cfg.header = h.Clone() }
+}
+
+// WithCompression negotiates permessage-deflate compression.
+func WithCompression(enabled bool) Option {
+	return func(c *Client) { c.cfg.compression = enabled }
+}
+
+// WithReadLimit caps the size in bytes of a single inbound message; larger
+// messages drop the connection. Default unlimited.
+func WithReadLimit(n int64) Option {
+	return func(c *Client) { c.cfg.readLimit = n }
+}
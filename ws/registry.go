@@ -0,0 +1,204 @@
+package ws
+
+import (
+	"sort"
+	"sync"
+)
+
+// allMarkets is the registry key for a user-channel subscription with no
+// market filter, which the server treats as "every market".
+const allMarkets = ""
+
+// subKey identifies one refcounted interest: an asset ID (market channel) or
+// condition ID (user channel) for one event type.
+type subKey struct {
+	id        string
+	eventType string
+}
+
+// registry reference-counts subscriptions on one connection. The server only
+// knows about IDs, not event types, so wire messages are sent when an ID's
+// total count across event types moves between 0 and 1.
+type registry struct {
+	channel string
+
+	mu     sync.Mutex
+	counts map[subKey]int
+	totals map[string]int
+	auth   *AuthPayload
+
+	// authFn, if set, supplies the client's current credentials. They take
+	// precedence over auth, so every subscribe (including resubscribe after a
+	// reconnect) carries the latest rotation.
+	authFn func() *AuthPayload
+}
+
+func newRegistry(channel string) *registry {
+	return &registry{
+		channel: channel,
+		counts:  make(map[subKey]int),
+		totals:  make(map[string]int),
+	}
+}
+
+// requestIDs extracts the subscribed IDs from a request for this channel.
+func (r *registry) requestIDs(req SubscriptionRequest) []string {
+	ids := req.AssetsIDs
+	if r.channel == ChannelUser {
+		ids = req.Markets
+		if len(ids) == 0 {
+			ids = []string{allMarkets}
+		}
+	}
+	return dedupe(ids)
+}
+
+// acquire adds one reference per ID for eventType and returns the IDs that
+// were not subscribed before.
+func (r *registry) acquire(ids []string, eventType string, auth *AuthPayload) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if auth != nil {
+		r.auth = auth
+	}
+	var added []string
+	for _, id := range ids {
+		r.counts[subKey{id, eventType}]++
+		r.totals[id]++
+		if r.totals[id] == 1 {
+			added = append(added, id)
+		}
+	}
+	return added
+}
+
+// release drops one reference per ID for eventType and returns the IDs no
+// longer needed by anyone. IDs that were already force-removed are ignored.
+func (r *registry) release(ids []string, eventType string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var removed []string
+	for _, id := range ids {
+		key := subKey{id, eventType}
+		if r.counts[key] == 0 {
+			continue
+		}
+		r.counts[key]--
+		if r.counts[key] == 0 {
+			delete(r.counts, key)
+		}
+		r.totals[id]--
+		if r.totals[id] == 0 {
+			delete(r.totals, id)
+			removed = append(removed, id)
+		}
+	}
+	return removed
+}
+
+// remove drops every reference to the given IDs regardless of count and
+// returns those that were subscribed.
+func (r *registry) remove(ids []string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var removed []string
+	for _, id := range dedupe(ids) {
+		if r.totals[id] == 0 {
+			continue
+		}
+		delete(r.totals, id)
+		for key := range r.counts {
+			if key.id == id {
+				delete(r.counts, key)
+			}
+		}
+		removed = append(removed, id)
+	}
+	return removed
+}
+
+// active returns every subscribed ID, sorted.
+func (r *registry) active() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, 0, len(r.totals))
+	for id := range r.totals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// held reports whether any consumer holds id.
+func (r *registry) held(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.totals[id] > 0
+}
+
+// refs returns the reference count for id and eventType.
+func (r *registry) refs(id, eventType string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[subKey{id, eventType}]
+}
+
+// request builds a subscribe or unsubscribe message for ids.
+func (r *registry) request(op string, ids []string) SubscriptionRequest {
+	req := SubscriptionRequest{
+		Type:      r.channel,
+		Operation: op,
+		AssetsIDs: []string{},
+		Markets:   []string{},
+	}
+	if op == OpSubscribe {
+		initialDump := true
+		req.InitialDump = &initialDump
+	}
+	if r.channel == ChannelMarket {
+		req.AssetsIDs = ids
+		return req
+	}
+	wildcard := false
+	for _, id := range ids {
+		if id == allMarkets {
+			wildcard = true
+			continue
+		}
+		req.Markets = append(req.Markets, id)
+	}
+	if op == OpSubscribe {
+		if wildcard {
+			// An unfiltered subscription covers every market.
+			req.Markets = []string{}
+		}
+		r.mu.Lock()
+		req.Auth = r.auth
+		r.mu.Unlock()
+		if r.authFn != nil {
+			if auth := r.authFn(); auth != nil {
+				req.Auth = auth
+			}
+		}
+	}
+	return req
+}
+
+// empty reports whether req names no IDs. An empty unsubscribe is never sent,
+// since the server could read it as "everything".
+func (req SubscriptionRequest) empty() bool {
+	return len(req.AssetsIDs) == 0 && len(req.Markets) == 0
+}
+
+func dedupe(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
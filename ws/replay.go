@@ -0,0 +1,88 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/lubluniky/clob-client-go/replay"
+)
+
+// WithRecorder records every inbound and outbound frame (other than PING/PONG
+// heartbeats) on all channels. Subscription credentials are redacted.
+func WithRecorder(rec *replay.Recorder) Option {
+	return func(c *Client) { c.recorder = rec }
+}
+
+// WithReplay serves recorded inbound frames instead of dialing the network.
+// Each channel starts replaying once its first subscription is sent, serves
+// the frames recorded for its URL path once and in order, then stays
+// connected and idle until the client is closed.
+func WithReplay(rp *replay.Replayer) Option {
+	return func(c *Client) { c.replayer = rp }
+}
+
+// replayDialer returns a dial function that opens replayed sockets.
+func replayDialer(rp *replay.Replayer) func(context.Context, string) (wsConn, error) {
+	return func(_ context.Context, url string) (wsConn, error) {
+		return &replayConn{
+			rp:         rp,
+			url:        url,
+			subscribed: make(chan struct{}),
+			done:       make(chan struct{}),
+		}, nil
+	}
+}
+
+var errReplayClosed = errors.New("ws: replay connection closed")
+
+// replayConn is a wsConn backed by a recording. Writes are accepted and
+// discarded; heartbeats are never answered, so the PONG timeout never fires.
+type replayConn struct {
+	rp  *replay.Replayer
+	url string
+
+	// subscribed is closed by the first write so frames are not dispatched
+	// before any listener exists.
+	subscribed chan struct{}
+	subOnce    sync.Once
+
+	done chan struct{}
+	once sync.Once
+}
+
+func (r *replayConn) ReadMessage() (int, []byte, error) {
+	select {
+	case <-r.subscribed:
+	case <-r.done:
+		return 0, nil, errReplayClosed
+	}
+	if data, ok := r.rp.NextWSFrame(r.url); ok {
+		return websocket.TextMessage, data, nil
+	}
+	<-r.done
+	return 0, nil, errReplayClosed
+}
+
+func (r *replayConn) WriteMessage(_ int, data []byte) error {
+	select {
+	case <-r.done:
+		return errReplayClosed
+	default:
+	}
+	if string(data) != "PING" {
+		r.subOnce.Do(func() { close(r.subscribed) })
+	}
+	return nil
+}
+
+// SetReadDeadline is a no-op: replayed sockets never stall.
+func (r *replayConn) SetReadDeadline(time.Time) error { return nil }
+
+func (r *replayConn) Close() error {
+	r.once.Do(func() { close(r.done) })
+	return nil
+}
@@ -0,0 +1,196 @@
+package ws
+
+// scanner is a minimal allocation-free JSON reader for the fixed shapes the
+// server sends. Strings are returned as slices of the input; any string with
+// an escape sequence makes the read fail, so callers fall back to
+// encoding/json.
+type scanner struct {
+	b []byte
+	i int
+}
+
+func (s *scanner) space() {
+	for s.i < len(s.b) {
+		switch s.b[s.i] {
+		case ' ', '\t', '\n', '\r':
+			s.i++
+		default:
+			return
+		}
+	}
+}
+
+// consume skips whitespace and then c, reporting whether c was next.
+func (s *scanner) consume(c byte) bool {
+	s.space()
+	if s.i < len(s.b) && s.b[s.i] == c {
+		s.i++
+		return true
+	}
+	return false
+}
+
+// str reads a string without escapes and returns its contents.
+func (s *scanner) str() ([]byte, bool) {
+	if !s.consume('"') {
+		return nil, false
+	}
+	start := s.i
+	for s.i < len(s.b) {
+		switch s.b[s.i] {
+		case '"':
+			v := s.b[start:s.i]
+			s.i++
+			return v, true
+		case '\\':
+			return nil, false
+		}
+		s.i++
+	}
+	return nil, false
+}
+
+// skipString skips a string, escapes included.
+func (s *scanner) skipString() bool {
+	if !s.consume('"') {
+		return false
+	}
+	for s.i < len(s.b) {
+		c := s.b[s.i]
+		s.i++
+		switch c {
+		case '\\':
+			s.i++
+		case '"':
+			return true
+		}
+	}
+	return false
+}
+
+// skip skips one value of any type.
+func (s *scanner) skip() bool {
+	s.space()
+	if s.i >= len(s.b) {
+		return false
+	}
+	switch s.b[s.i] {
+	case '"':
+		return s.skipString()
+	case '{', '[':
+		depth := 0
+		for s.i < len(s.b) {
+			switch s.b[s.i] {
+			case '"':
+				if !s.skipString() {
+					return false
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					s.i++
+					return true
+				}
+			}
+			s.i++
+		}
+		return false
+	default:
+		// Number or literal.
+		start := s.i
+		for s.i < len(s.b) {
+			switch s.b[s.i] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return s.i > start
+			}
+			s.i++
+		}
+		return s.i > start
+	}
+}
+
+// object reads an object, calling field for each key with the cursor on the
+// value; field must consume the value. Returning false from field stops the
+// read and makes object return false.
+func (s *scanner) object(field func(key []byte) bool) bool {
+	if !s.consume('{') {
+		return false
+	}
+	if s.consume('}') {
+		return true
+	}
+	for {
+		key, ok := s.str()
+		if !ok || !s.consume(':') || !field(key) {
+			return false
+		}
+		if s.consume(',') {
+			continue
+		}
+		return s.consume('}')
+	}
+}
+
+// array reads an array, calling elem with the cursor on each element; elem
+// must consume it.
+func (s *scanner) array(elem func() bool) bool {
+	if !s.consume('[') {
+		return false
+	}
+	if s.consume(']') {
+		return true
+	}
+	for {
+		if !elem() {
+			return false
+		}
+		if s.consume(',') {
+			continue
+		}
+		return s.consume(']')
+	}
+}
+
+// sniffEventType returns the top-level event_type of a single event frame
+// without decoding the rest. ok is false for malformed frames; a well-formed
+// frame without event_type yields "".
+func sniffEventType(data []byte) (eventType string, ok bool) {
+	s := scanner{b: data}
+	var found []byte
+	done := false
+	ok = s.object(func(key []byte) bool {
+		if string(key) != "event_type" {
+			return s.skip()
+		}
+		found, done = s.str()
+		// Stop early: the remaining fields are not needed.
+		return false
+	})
+	if done {
+		return internEventType(found), true
+	}
+	return "", ok
+}
+
+// internEventType maps known event types to their constants so sniffing
+// does not allocate.
+func internEventType(b []byte) string {
+	switch string(b) {
+	case EventBook:
+		return EventBook
+	case EventPriceChange:
+		return EventPriceChange
+	case EventTickSizeChange:
+		return EventTickSizeChange
+	case EventLastTradePrice:
+		return EventLastTradePrice
+	case EventOrder:
+		return EventOrder
+	case EventTrade:
+		return EventTrade
+	}
+	return string(b)
+}
@@ -0,0 +1,191 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// WithMarketShards spreads market channel assets across at least n sockets,
+// each with its own read goroutine and reconnect loop. New assets go to the
+// least-loaded shard. The default is a single socket.
+func WithMarketShards(n int) Option {
+	return func(c *Client) { c.minShards = n }
+}
+
+// WithMaxAssetsPerConn caps the number of assets subscribed on one market
+// socket; further shards are opened as needed. Zero (the default) means no cap.
+func WithMaxAssetsPerConn(n int) Option {
+	return func(c *Client) { c.maxPerShard = n }
+}
+
+// shardGroup is the slice of a subscription that lives on one shard.
+type shardGroup struct {
+	conn *connection
+	ids  []string
+}
+
+// getMarketConn returns the first market shard, creating it if needed.
+// By default connections use context.Background and live until Client.Close()
+// is called. With WithConnectionContext, lifecycle follows the configured
+// context.
+func (c *Client) getMarketConn(_ context.Context) *connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureShardsLocked()
+	return c.marketShards[0]
+}
+
+// ensureShardsLocked opens shards up to the configured minimum.
+func (c *Client) ensureShardsLocked() {
+	if c.shardOf == nil {
+		c.shardOf = make(map[string]int)
+	}
+	for len(c.marketShards) < max(c.minShards, 1) {
+		c.addShardLocked()
+	}
+	for i, shard := range c.marketShards {
+		if shard.gaveUp() {
+			c.replaceShardLocked(i)
+		}
+	}
+}
+
+// replaceShardLocked swaps a shard that gave up for a fresh connection. Its
+// assets lost their subscriptions and are unassigned.
+func (c *Client) replaceShardLocked(i int) {
+	conn := c.newConnection(ChannelMarket, i)
+	conn.onIdle = func(ids []string) { c.unassign(conn, ids) }
+	c.marketShards[i] = conn
+	c.shardLoad[i] = 0
+	for id, idx := range c.shardOf {
+		if idx == i {
+			delete(c.shardOf, id)
+		}
+	}
+}
+
+func (c *Client) addShardLocked() int {
+	c.marketShards = append(c.marketShards, nil)
+	c.shardLoad = append(c.shardLoad, 0)
+	idx := len(c.marketShards) - 1
+	c.replaceShardLocked(idx)
+	return idx
+}
+
+// assignLocked returns the shard for assetID, placing new assets on the
+// least-loaded shard below the cap.
+func (c *Client) assignLocked(assetID string) int {
+	if i, ok := c.shardOf[assetID]; ok {
+		return i
+	}
+	best := -1
+	for i, load := range c.shardLoad {
+		if c.maxPerShard > 0 && load >= c.maxPerShard {
+			continue
+		}
+		if best < 0 || load < c.shardLoad[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		best = c.addShardLocked()
+	}
+	c.shardOf[assetID] = best
+	c.shardLoad[best]++
+	return best
+}
+
+// unassign frees shard slots for assets that conn no longer carries. An asset
+// re-acquired in the meantime keeps its slot.
+func (c *Client) unassign(conn *connection, ids []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		i, ok := c.shardOf[id]
+		if !ok || i >= len(c.marketShards) || c.marketShards[i] != conn || conn.reg.held(id) {
+			continue
+		}
+		delete(c.shardOf, id)
+		c.shardLoad[i]--
+	}
+}
+
+// subscribeMarket subscribes req's assets on their shards and merges the
+// per-shard streams into one channel.
+func (c *Client) subscribeMarket(ctx context.Context, req SubscriptionRequest, eventType string) <-chan json.RawMessage {
+	return subscribeSharded(c, ctx, req, func(conn *connection, part SubscriptionRequest) <-chan json.RawMessage {
+		return conn.subscribe(ctx, part, eventType)
+	})
+}
+
+// subscribeSharded splits req's assets by shard, subscribes each part with
+// sub and merges the results. Assignment and reference acquisition happen
+// under c.mu so an asset never ends up on two shards. Ordering is preserved
+// per shard, and therefore per asset.
+func subscribeSharded[T any](c *Client, ctx context.Context, req SubscriptionRequest, sub func(*connection, SubscriptionRequest) <-chan T) <-chan T {
+	c.mu.Lock()
+	c.ensureShardsLocked()
+	var groups []shardGroup
+	index := make(map[int]int)
+	for _, id := range dedupe(req.AssetsIDs) {
+		i := c.assignLocked(id)
+		g, ok := index[i]
+		if !ok {
+			g = len(groups)
+			index[i] = g
+			groups = append(groups, shardGroup{conn: c.marketShards[i]})
+		}
+		groups[g].ids = append(groups[g].ids, id)
+	}
+	if len(groups) == 0 {
+		groups = append(groups, shardGroup{conn: c.marketShards[0]})
+	}
+
+	streams := make([]<-chan T, len(groups))
+	for i, g := range groups {
+		part := req
+		part.AssetsIDs = g.ids
+		if part.AssetsIDs == nil {
+			part.AssetsIDs = []string{}
+		}
+		streams[i] = sub(g.conn, part)
+	}
+	c.mu.Unlock()
+
+	if len(streams) == 1 {
+		return streams[0]
+	}
+	return mergeStreams(ctx, streams)
+}
+
+// mergeStreams fans several listener channels into one, closed once all
+// inputs are closed. After ctx ends, remaining input is drained and dropped.
+func mergeStreams[T any](ctx context.Context, streams []<-chan T) <-chan T {
+	out := make(chan T, channelBufferSize)
+	var wg sync.WaitGroup
+	wg.Add(len(streams))
+	for _, s := range streams {
+		go func(s <-chan T) {
+			defer wg.Done()
+			for msg := range s {
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+				}
+			}
+		}(s)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// MarketShards returns the number of open market channel sockets.
+func (c *Client) MarketShards() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.marketShards)
+}
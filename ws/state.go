@@ -0,0 +1,187 @@
+package ws
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ConnState is the lifecycle state of one WebSocket connection.
+type ConnState int
+
+const (
+	// StateConnecting is the initial dial, before the first successful connect.
+	StateConnecting ConnState = iota
+	// StateConnected means the socket is open but no subscription has been
+	// sent on it yet.
+	StateConnected
+	// StateSubscribed means the socket is open and carries at least one
+	// subscription.
+	StateSubscribed
+	// StateReconnecting means the socket dropped or a re-dial failed and the
+	// connection is backing off.
+	StateReconnecting
+	// StateClosed is terminal: the client was closed, the connection context
+	// ended, or the backoff policy gave up.
+	StateClosed
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateSubscribed:
+		return "subscribed"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnStatus is a health snapshot of one connection.
+type ConnStatus struct {
+	Channel string // ChannelMarket or ChannelUser
+	// Shard is the market shard index; always 0 on the user channel.
+	Shard int
+	URL   string
+	State ConnState
+	// Since is when the connection entered State.
+	Since time.Time
+	// LastMessage is when the last frame (including PONG) was received.
+	LastMessage time.Time
+	// PongRTT is the round trip of the most recent PING/PONG exchange.
+	PongRTT time.Duration
+	// Reconnects counts dropped connections and failed re-dials.
+	Reconnects int
+	// LastError is the most recent dial or read error; it is not cleared on
+	// reconnect.
+	LastError error
+}
+
+// health tracks the state of one connection.
+type health struct {
+	mu          sync.Mutex
+	state       ConnState
+	since       time.Time
+	lastMessage time.Time
+	pingSent    time.Time
+	pongRTT     time.Duration
+	reconnects  int
+	lastErr     error
+}
+
+// status returns a snapshot of the connection's health.
+func (c *connection) status() ConnStatus {
+	c.health.mu.Lock()
+	defer c.health.mu.Unlock()
+	return c.statusLocked()
+}
+
+func (c *connection) statusLocked() ConnStatus {
+	h := &c.health
+	return ConnStatus{
+		Channel:     c.reg.channel,
+		Shard:       c.shard,
+		URL:         c.url,
+		State:       h.state,
+		Since:       h.since,
+		LastMessage: h.lastMessage,
+		PongRTT:     h.pongRTT,
+		Reconnects:  h.reconnects,
+		LastError:   h.lastErr,
+	}
+}
+
+// setState moves the connection to state, recording err when non-nil, and
+// reports the change to onState. Closed is terminal.
+func (c *connection) setState(state ConnState, err error) {
+	h := &c.health
+	h.mu.Lock()
+	if h.state == StateClosed {
+		h.mu.Unlock()
+		return
+	}
+	if err != nil {
+		h.lastErr = err
+	}
+	if state == StateReconnecting {
+		h.reconnects++
+	}
+	changed := h.state != state || h.since.IsZero()
+	if changed {
+		h.state = state
+		h.since = time.Now()
+	}
+	st := c.statusLocked()
+	h.mu.Unlock()
+
+	if changed && c.onState != nil {
+		c.onState(st)
+	}
+}
+
+// markSubscribed moves a connected socket to Subscribed after a subscribe
+// message was written.
+func (c *connection) markSubscribed() {
+	c.health.mu.Lock()
+	connected := c.health.state == StateConnected
+	c.health.mu.Unlock()
+	if connected {
+		c.setState(StateSubscribed, nil)
+	}
+}
+
+// markMessage records an inbound frame. A PONG also completes the RTT of the
+// outstanding PING.
+func (c *connection) markMessage(at time.Time, pong bool) {
+	c.health.mu.Lock()
+	c.health.lastMessage = at
+	if pong && !c.health.pingSent.IsZero() {
+		c.health.pongRTT = at.Sub(c.health.pingSent)
+		c.health.pingSent = time.Time{}
+	}
+	c.health.mu.Unlock()
+}
+
+// markPing records when a PING was sent.
+func (c *connection) markPing(at time.Time) {
+	c.health.mu.Lock()
+	c.health.pingSent = at
+	c.health.mu.Unlock()
+}
+
+// State returns a health snapshot of every open connection: the user channel
+// first (if opened), then each market shard in index order.
+func (c *Client) State() []ConnStatus {
+	c.mu.Lock()
+	conns := make([]*connection, 0, len(c.marketShards)+1)
+	if c.userConn != nil {
+		conns = append(conns, c.userConn)
+	}
+	conns = append(conns, c.marketShards...)
+	c.mu.Unlock()
+
+	out := make([]ConnStatus, 0, len(conns))
+	for _, conn := range conns {
+		out = append(out, conn.status())
+	}
+	return out
+}
+
+// StateChanges streams a ConnStatus every time a connection changes state,
+// on any channel or shard. The channel closes when ctx ends or the client is
+// closed. Slow consumers miss transitions rather than stalling the
+// connections; call State for the current picture.
+func (c *Client) StateChanges(ctx context.Context) <-chan ConnStatus {
+	ch, stop := c.states.subscribe()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ch
+}
@@ -2,30 +2,42 @@ package ws
 
 import "context"
 
-// UnsubscribeMarket sends an unsubscribe message for the given asset IDs on the market channel.
+// UnsubscribeMarket unsubscribes the given asset IDs on the market channel for
+// every consumer, regardless of reference counts. Other assets are unaffected.
+// To release a single consumer's interest, cancel the context passed to its
+// Subscribe call instead.
 func (c *Client) UnsubscribeMarket(ctx context.Context, assetIDs ...string) error {
 	c.mu.Lock()
-	conn := c.marketConn
-	c.mu.Unlock()
-	if conn == nil {
-		return nil
-	}
-	req := SubscriptionRequest{
-		Type:      ChannelMarket,
-		Operation: OpUnsubscribe,
-		AssetsIDs: assetIDs,
-		Markets:   []string{},
+	byShard := make(map[*connection][]string)
+	var order []*connection
+	for _, id := range dedupe(assetIDs) {
+		i, ok := c.shardOf[id]
+		if !ok {
+			continue
+		}
+		conn := c.marketShards[i]
+		if _, seen := byShard[conn]; !seen {
+			order = append(order, conn)
+		}
+		byShard[conn] = append(byShard[conn], id)
+		delete(c.shardOf, id)
+		c.shardLoad[i]--
 	}
-	err := conn.sendJSON(req)
-	if err != nil {
-		return err
+	c.mu.Unlock()
+
+	var firstErr error
+	for _, conn := range order {
+		// Drop tracking first so a reconnect won't re-subscribe them.
+		if err := conn.unsubscribe(conn.reg.remove(byShard[conn])); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	// Remove matching subscriptions from tracking so reconnect won't re-subscribe them.
-	conn.removeTrackedAssets(assetIDs)
-	return nil
+	return firstErr
 }
 
-// UnsubscribeUser sends an unsubscribe message for the given markets on the user channel.
+// UnsubscribeUser unsubscribes the given markets on the user channel for every
+// consumer, regardless of reference counts. To release a single consumer's
+// interest, cancel the context passed to its Subscribe call instead.
 func (c *Client) UnsubscribeUser(ctx context.Context, markets ...string) error {
 	c.mu.Lock()
 	conn := c.userConn
@@ -33,17 +45,5 @@ func (c *Client) UnsubscribeUser(ctx context.Context, markets ...string) error {
 	if conn == nil {
 		return nil
 	}
-	req := SubscriptionRequest{
-		Type:      ChannelUser,
-		Operation: OpUnsubscribe,
-		AssetsIDs: []string{},
-		Markets:   markets,
-	}
-	err := conn.sendJSON(req)
-	if err != nil {
-		return err
-	}
-	// Remove matching subscriptions from tracking so reconnect won't re-subscribe them.
-	conn.removeTrackedMarkets(markets)
-	return nil
+	return conn.unsubscribe(conn.reg.remove(markets))
 }
@@ -0,0 +1,21 @@
+package ws
+
+import "context"
+
+// TickSizeSink receives tick size changes. *polymarket.MarketCache
+// implements it.
+type TickSizeSink interface {
+	ApplyTickSizeChange(tokenID, tickSize string) error
+}
+
+// ApplyTickSizeChanges subscribes to tick size changes for assetIDs and
+// applies each one to sink, blocking until ctx ends or the client is closed.
+// Changes the sink rejects (for example a malformed tick size) are skipped
+// and passed to onReject with the sink's error; onReject may be nil.
+func (c *Client) ApplyTickSizeChanges(ctx context.Context, sink TickSizeSink, onReject func(TickSizeChange, error), assetIDs ...string) {
+	for change := range c.SubscribeTickSizeChange(ctx, assetIDs...) {
+		if err := sink.ApplyTickSizeChange(change.AssetID, change.NewTickSize); err != nil && onReject != nil {
+			onReject(change, err)
+		}
+	}
+}